@@ -0,0 +1,58 @@
+package faunadb
+
+import "sync"
+
+// IndexedResult is the result of one of the queries sent by BatchQueryChan, along with the position of its
+// expression in the batch.
+type IndexedResult struct {
+	Index int   // Index of the expression in the batch
+	Value Value // Value returned by the query, or nil if it failed
+	Err   error // Error returned by the query, if any
+}
+
+/*
+BatchQueryChan sends each query language expression informed as a separate query, returning a channel that receives
+their results as they complete, in any order, so callers can process early results while others are in flight. Each
+result carries the index of its expression. At most the number of queries informed by concurrency are sent at the
+same time; a concurrency of zero or less sends one query at a time. The channel is closed after every result is sent.
+It accepts the same configurations as Query, applied to each query.
+
+Unlike BatchQuery, queries are not sent in a single transaction: some may fail while others succeed. The channel
+is buffered to hold every result, so abandoning it does not block the queries still in flight.
+*/
+func (client *FaunaClient) BatchQueryChan(exprs []Expr, concurrency int, configs ...QueryConfig) <-chan IndexedResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make(chan IndexedResult, len(exprs))
+	indexes := make(chan int, len(exprs))
+
+	for i := range exprs {
+		indexes <- i
+	}
+
+	close(indexes)
+
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < concurrency && worker < len(exprs); worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				value, err := client.Query(exprs[i], configs...)
+				results <- IndexedResult{Index: i, Value: value, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,99 @@
+package faunadb
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectResults(results <-chan IndexedResult) []IndexedResult {
+	var collected []IndexedResult
+
+	for result := range results {
+		collected = append(collected, result)
+	}
+
+	return collected
+}
+
+func TestBatchQueryChanEmitsResultsAsTheyComplete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		if strings.Contains(string(body), "slow") {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		_, _ = w.Write([]byte(`{"resource": ` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	client := NewFaunaClient("secret", Endpoint(server.URL))
+
+	results := collectResults(client.BatchQueryChan([]Expr{StringV("slow"), StringV("fast")}, 2))
+
+	require.Equal(t,
+		[]IndexedResult{
+			{Index: 1, Value: StringV("fast")},
+			{Index: 0, Value: StringV("slow")},
+		},
+		results,
+	)
+}
+
+func TestBatchQueryChanReportsErrorsByIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		if strings.Contains(string(body), "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": [{"code": "instance not found", "description": "Instance not found."}]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"resource": ` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	client := NewFaunaClient("secret", Endpoint(server.URL))
+
+	results := make([]IndexedResult, 3)
+	for result := range client.BatchQueryChan([]Expr{LongV(1), StringV("missing"), LongV(3)}, 3) {
+		results[result.Index] = result
+	}
+
+	require.Equal(t, LongV(1), results[0].Value)
+	require.NoError(t, results[0].Err)
+	require.Nil(t, results[1].Value)
+	require.IsType(t, NotFound{}, results[1].Err)
+	require.Equal(t, LongV(3), results[2].Value)
+	require.NoError(t, results[2].Err)
+}
+
+func TestBatchQueryChanBoundsConcurrency(t *testing.T) {
+	server := newInFlightServer(20*time.Millisecond, `{"resource": null}`)
+	defer server.Close()
+
+	client := NewFaunaClient("secret", Endpoint(server.URL))
+
+	exprs := make([]Expr, 10)
+	for i := range exprs {
+		exprs[i] = LongV(i)
+	}
+
+	results := collectResults(client.BatchQueryChan(exprs, 3))
+
+	require.Len(t, results, 10)
+	require.Equal(t, 3, server.maxInFlight())
+}
+
+func TestBatchQueryChanClosesEmptyBatches(t *testing.T) {
+	client := NewFaunaClient("secret", Endpoint("http://localhost:1"))
+
+	require.Empty(t, collectResults(client.BatchQueryChan(nil, 2)))
+}
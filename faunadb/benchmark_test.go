@@ -69,7 +69,7 @@ var (
 
 	benchmarkData = benchmarkStruct{
 		TaggedString: "TaggedString",
-		Ref:          RefV{"classes/spells/42"},
+		Ref:          RefV{ID: "classes/spells/42"},
 		Any:          StringV("any value"),
 		Date:         time.Date(1970, time.January, 3, 0, 0, 0, 0, time.UTC),
 		Time:         time.Date(1970, time.January, 1, 0, 0, 0, 5, time.UTC),
@@ -117,6 +117,28 @@ func BenchmarkDecodeValue(b *testing.B) {
 	}
 }
 
+func BenchmarkDecodeStructSlice(b *testing.B) {
+	value, err := parseJSON(bytes.NewReader(benckmarkJSON))
+	if err != nil {
+		panic(err)
+	}
+
+	arr := make(ArrayV, 1000)
+	for i := range arr {
+		arr[i] = value
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var objs []benchmarkStruct
+
+		if err := arr.Get(&objs); err != nil {
+			panic(err)
+		}
+	}
+}
+
 func BenchmarkEncodeValue(b *testing.B) {
 	expr := Obj{"data": benchmarkData}
 
@@ -0,0 +1,197 @@
+package faunadb
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultQueryCacheSize = 1000
+
+// QueryCacheSize configures the FaunaClient structure to keep at most the number of results informed in the cache
+// used by QueryCached, evicting the least recently used results first. A size of zero or less disables the cache.
+func QueryCacheSize(size int) ClientConfig {
+	return func(cli *FaunaClient) { cli.cacheSize = &size }
+}
+
+/*
+QueryCached sends a query language expression to FaunaDB as Query does, caching its result in memory for the
+duration informed. Subsequent calls with an expression encoded to the same JSON return the cached result, without
+sending it to FaunaDB, until it expires according to the client's Clock.
+
+Only results of queries reported as read-only by FaunaDB are cached, as described by QueryResult.ReadOnly; queries
+that write are always sent. Configurations that change the result of a query, such as Consistency and UnwrapData, are
+part of the cache key, so the same expression sent with different configurations is cached separately. DryRun queries
+are never cached nor served from the cache. Cached values are copied when cached and when returned, so callers may
+modify the results they receive without changing the cache.
+
+The cache holds up to 1000 results by default, which can be changed with the QueryCacheSize configuration.
+Session clients have caches of their own, and SetSecret discards the results cached by the client.
+*/
+func (client *FaunaClient) QueryCached(expr Expr, ttl time.Duration, configs ...QueryConfig) (value Value, err error) {
+	var body []byte
+	var result QueryResult
+
+	cfg := newQueryConfig(configs)
+
+	if cfg.dryRun {
+		return client.Query(expr, configs...)
+	}
+
+	if body, err = marshalJSON(expr); err != nil {
+		return
+	}
+
+	key := cfg.cacheKey(body)
+
+	if cached, found := client.cache.get(key, client.clock()); found {
+		return copyValue(cached), nil
+	}
+
+	if result, err = client.QueryWithResult(expr, configs...); err == nil {
+		value = result.Value
+
+		if result.ReadOnly && ttl > 0 {
+			client.cache.put(key, copyValue(value), client.clock().Add(ttl))
+		}
+	}
+
+	return
+}
+
+// cacheKey prefixes the body informed with the configurations that change the result of a query. Tags and
+// idempotency keys do not change results, so they are left out.
+func (cfg *queryConfig) cacheKey(body []byte) string {
+	return fmt.Sprintf("%s|%t|%t|%s", cfg.consistency, cfg.unwrapData, cfg.fql, body)
+}
+
+// copyValue returns a deep copy of the value informed, so that modifying the copy does not modify the original.
+func copyValue(value Value) Value {
+	switch v := value.(type) {
+	case ObjectV:
+		return ObjectV(copyValues(v))
+	case ArrayV:
+		if v == nil {
+			return v
+		}
+
+		arr := make(ArrayV, len(v))
+
+		for i, elem := range v {
+			arr[i] = copyValue(elem)
+		}
+
+		return arr
+	case SetRefV:
+		return SetRefV{copyValues(v.Parameters)}
+	case RefV:
+		return copyRef(v)
+	case BytesV:
+		if v == nil {
+			return v
+		}
+
+		return append(BytesV{}, v...)
+	default:
+		return v
+	}
+}
+
+func copyValues(values map[string]Value) map[string]Value {
+	if values == nil {
+		return nil
+	}
+
+	copied := make(map[string]Value, len(values))
+
+	for key, elem := range values {
+		copied[key] = copyValue(elem)
+	}
+
+	return copied
+}
+
+func copyRef(ref RefV) RefV {
+	if ref.Collection != nil {
+		collection := copyRef(*ref.Collection)
+		ref.Collection = &collection
+	}
+
+	if ref.Database != nil {
+		database := copyRef(*ref.Database)
+		ref.Database = &database
+	}
+
+	return ref
+}
+
+type queryCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // Most recently used entries first
+}
+
+type queryCacheEntry struct {
+	key     string
+	value   Value
+	expires time.Time
+}
+
+func newQueryCache(size int) *queryCache {
+	return &queryCache{size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (cache *queryCache) get(key string, now time.Time) (Value, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, found := cache.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := element.Value.(*queryCacheEntry)
+
+	if !now.Before(entry.expires) {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (cache *queryCache) put(key string, value Value, expires time.Time) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.size <= 0 {
+		return
+	}
+
+	if element, found := cache.entries[key]; found {
+		entry := element.Value.(*queryCacheEntry)
+		entry.value, entry.expires = value, expires
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&queryCacheEntry{key, value, expires})
+
+	for cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*queryCacheEntry).key)
+	}
+}
+
+func (cache *queryCache) clear() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries = make(map[string]*list.Element)
+	cache.order.Init()
+}
@@ -0,0 +1,272 @@
+package faunadb
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var readOnlyHeaders = http.Header{"X-Byte-Write-Ops": {"0"}}
+
+func TestQueryCachedReturnsCachedResults(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	value, err := client.QueryCached(Get(Ref("classes/spells/42")), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("first"), value)
+
+	value, err = client.QueryCached(Get(Ref("classes/spells/42")), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("first"), value)
+	require.Len(t, server.requestBodies(), 1)
+}
+
+func TestQueryCachedResultsCanBeModified(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders,
+		`{"resource": {"name": "Fire", "tags": ["hot"], "ref": {"@ref": {"id": "42", "collection": {"@ref": {"id": "spells"}}}}}}`,
+	)
+	defer server.Close()
+
+	client := server.client()
+	expected := ObjectV{
+		"name": StringV("Fire"),
+		"tags": ArrayV{StringV("hot")},
+		"ref":  RefV{ID: "42", Collection: &RefV{ID: "spells"}},
+	}
+
+	for i := 0; i < 2; i++ {
+		value, err := client.QueryCached(NullV{}, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, expected, value)
+
+		obj := value.(ObjectV)
+		obj["name"] = StringV("Water")
+		obj["tags"].(ArrayV)[0] = StringV("cold")
+		obj["ref"].(RefV).Collection.ID = "potions"
+	}
+
+	require.Len(t, server.requestBodies(), 1)
+}
+
+func TestQueryCachedResultsCanBeModifiedConcurrently(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": {"name": "Fire"}}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		go func() {
+			value, err := client.QueryCached(NullV{}, time.Minute)
+			if err == nil {
+				value.(ObjectV)["name"] = StringV("Water")
+			}
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, <-errs)
+	}
+}
+
+func TestQueryCachedSendsDifferentExpressions(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.QueryCached(Get(Ref("classes/spells/42")), time.Minute)
+	require.NoError(t, err)
+
+	value, err := client.QueryCached(Get(Ref("classes/spells/43")), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("second"), value)
+	require.Len(t, server.requestBodies(), 2)
+}
+
+func TestQueryCachedExpiresResults(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	now := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+	client := server.client(Clock(func() time.Time { return now }))
+
+	_, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+
+	now = now.Add(59 * time.Second)
+
+	value, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("first"), value)
+
+	now = now.Add(time.Second)
+
+	value, err = client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("second"), value)
+	require.Len(t, server.requestBodies(), 2)
+}
+
+func TestQueryCachedDoesNotCacheWrites(t *testing.T) {
+	headers := http.Header{"X-Byte-Write-Ops": {"1"}}
+	server := newMockServerWithHeaders(headers, `{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+
+	value, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("second"), value)
+}
+
+func TestQueryCachedDoesNotCacheWithoutOpsHeaders(t *testing.T) {
+	server := newMockServer(`{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+
+	value, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("second"), value)
+}
+
+func TestQueryCachedEvictsLeastRecentlyUsedResults(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "result"}`)
+	defer server.Close()
+
+	client := server.client(QueryCacheSize(2))
+
+	for _, id := range []string{"1", "2", "1", "3", "1", "2"} {
+		_, err := client.QueryCached(Get(Ref("classes/spells/"+id)), time.Minute)
+		require.NoError(t, err)
+	}
+
+	// 1 and 2 are sent, 1 is cached, 3 evicts 2, 1 is cached, 2 evicts 3
+	require.Len(t, server.requestBodies(), 4)
+}
+
+func TestDisableQueryCache(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	client := server.client(QueryCacheSize(0))
+
+	_, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+
+	value, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("second"), value)
+}
+
+func TestSessionClientsHaveTheirOwnCache(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+
+	value, err := client.NewSessionClient("other-secret").QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("second"), value)
+}
+
+func TestSetSecretDiscardsCachedResults(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "first"}`, `{"resource": "second"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+
+	client.SetSecret("other-secret")
+
+	value, err := client.QueryCached(NullV{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("second"), value)
+}
+
+func TestQueryCachedSeparatesUnwrappedResults(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders,
+		`{"resource": {"data": {"name": "Fireball"}}}`,
+		`{"resource": {"data": {"name": "Frostbolt"}}}`,
+	)
+	defer server.Close()
+
+	client := server.client()
+
+	value, err := client.QueryCached(Get(Ref("classes/spells/42")), time.Minute, UnwrapData())
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"name": StringV("Fireball")}, value)
+
+	value, err = client.QueryCached(Get(Ref("classes/spells/42")), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"data": ObjectV{"name": StringV("Frostbolt")}}, value)
+
+	value, err = client.QueryCached(Get(Ref("classes/spells/42")), time.Minute, UnwrapData())
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"name": StringV("Fireball")}, value)
+	require.Len(t, server.requestBodies(), 2)
+}
+
+func TestQueryCachedSeparatesConsistencyLevels(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "strong"}`, `{"resource": "eventual"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.QueryCached(Get(Ref("classes/spells/42")), time.Minute)
+	require.NoError(t, err)
+
+	value, err := client.QueryCached(Get(Ref("classes/spells/42")), time.Minute, Consistency("eventual"))
+	require.NoError(t, err)
+	require.Equal(t, StringV("eventual"), value)
+	require.Len(t, server.requestBodies(), 2)
+}
+
+func TestQueryCachedSeparatesFQLQueries(t *testing.T) {
+	cfg := &queryConfig{}
+	fql := &queryConfig{fql: true}
+
+	require.NotEqual(t, cfg.cacheKey([]byte(`"query"`)), fql.cacheKey([]byte(`"query"`)))
+}
+
+func TestQueryCachedDoesNotCacheDryRuns(t *testing.T) {
+	server := newMockServerWithHeaders(readOnlyHeaders, `{"resource": "live"}`)
+	defer server.Close()
+
+	client := server.client()
+
+	value, err := client.QueryCached(Get(Ref("classes/spells/42")), time.Minute, DryRun())
+	require.NoError(t, err)
+	require.Equal(t, BytesV(`{"get":{"@ref":"classes/spells/42"}}`), value)
+
+	value, err = client.QueryCached(Get(Ref("classes/spells/42")), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, StringV("live"), value)
+
+	value, err = client.QueryCached(Get(Ref("classes/spells/42")), time.Minute, DryRun())
+	require.NoError(t, err)
+	require.Equal(t, BytesV(`{"get":{"@ref":"classes/spells/42"}}`), value)
+	require.Len(t, server.requestBodies(), 1)
+}
@@ -0,0 +1,43 @@
+package faunadb
+
+import (
+	"fmt"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Unicode normalizers. Usually used as a parameter for the CasefoldKey function.
+//
+// See: https://fauna.com/documentation/queries#string_functions
+const (
+	NormalizerNFD          = "NFD"
+	NormalizerNFC          = "NFC"
+	NormalizerNFKD         = "NFKD"
+	NormalizerNFKC         = "NFKC"
+	NormalizerNFKCCaseFold = "NFKCCaseFold"
+)
+
+/*
+CasefoldKey normalizes the string informed locally, the same way the Casefold function does on the server.
+It is meant to precompute index terms without a round-trip to FaunaDB.
+
+The normalizer must be one of the Normalizer constants, or empty for the server's default, NFKCCaseFold, which applies
+a compatibility composition followed by case folding. Other normalizers return an error.
+*/
+func CasefoldKey(str string, normalizer string) (string, error) {
+	switch normalizer {
+	case NormalizerNFD:
+		return norm.NFD.String(str), nil
+	case NormalizerNFC:
+		return norm.NFC.String(str), nil
+	case NormalizerNFKD:
+		return norm.NFKD.String(str), nil
+	case NormalizerNFKC:
+		return norm.NFKC.String(str), nil
+	case NormalizerNFKCCaseFold, "":
+		return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(str))), nil
+	default:
+		return "", fmt.Errorf("Error while normalizing string: Unknown normalizer \"%s\"", normalizer)
+	}
+}
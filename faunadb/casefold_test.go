@@ -0,0 +1,37 @@
+package faunadb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCasefoldKey(t *testing.T) {
+	tests := []struct {
+		str, normalizer, expected string
+	}{
+		{"Straße", NormalizerNFKCCaseFold, "strasse"},
+		{"ΣΑΣ", NormalizerNFKCCaseFold, "σασ"},
+		{"ﬁre", NormalizerNFKCCaseFold, "fire"},
+		{"Ⅻ", NormalizerNFKCCaseFold, "xii"},
+		{"HELLO", "", "hello"},
+		{"\u00c9", NormalizerNFD, "E\u0301"},
+		{"É", NormalizerNFC, "É"},
+		{"ﬁ", NormalizerNFKD, "fi"},
+		{"①", NormalizerNFKC, "1"},
+		{"E\u0301", NormalizerNFC, "\u00c9"},
+	}
+
+	for _, test := range tests {
+		key, err := CasefoldKey(test.str, test.normalizer)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, key, "%s with %s", test.str, test.normalizer)
+	}
+}
+
+func TestFailToCasefoldKeyWithUnknownNormalizer(t *testing.T) {
+	for _, normalizer := range []string{"unknown", "nfkc", "NFKCCasefold"} {
+		_, err := CasefoldKey("HELLO", normalizer)
+		require.EqualError(t, err, "Error while normalizing string: Unknown normalizer \""+normalizer+"\"")
+	}
+}
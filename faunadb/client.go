@@ -2,21 +2,47 @@ package faunadb
 
 import (
 	"bytes"
+	"crypto/rand"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	defaultEndpoint = "https://db.fauna.com"
 	requestTimeout  = 60 * time.Second
+	maxDrainBytes   = 64 << 10 // Bytes left unread in response bodies discarded before closing them
+
+	consistencyHeader    = "X-Fauna-Read-Consistency"
+	requestIDHeader      = "X-Request-Id"
+	tagsHeader           = "X-Fauna-Tags"
+	idempotencyKeyHeader = "Idempotency-Key"
+	readOpsHeader        = "X-Byte-Read-Ops"
+	writeOpsHeader       = "X-Byte-Write-Ops"
 )
 
-var resource = ObjKey("resource")
+// Read consistency levels. Usually used as a parameter for the Consistency query configuration.
+const (
+	ConsistencySerialized = "serialized"
+	ConsistencyEventual   = "eventual"
+)
+
+var (
+	resource = ObjKey("resource")
+
+	errEmptyRawQuery = errors.New("Error while sending raw query: Query body must not be empty")
+)
 
 // ClientConfig are used to apply specific configurations to the FaunaClient structure.
 type ClientConfig func(*FaunaClient)
@@ -27,65 +53,471 @@ func Endpoint(url string) ClientConfig { return func(cli *FaunaClient) { cli.end
 // HTTP configures the FaunaClient structure to use a specific http.Client.
 func HTTP(http *http.Client) ClientConfig { return func(cli *FaunaClient) { cli.http = http } }
 
+// Proxy configures the FaunaClient structure to send requests through the proxy returned by the function informed,
+// such as http.ProxyURL. It is only used by the http.Client created by the driver: a http.Client provided with
+// the HTTP configuration keeps its own transport.
+func Proxy(proxy func(*http.Request) (*url.URL, error)) ClientConfig {
+	return func(cli *FaunaClient) { cli.proxy = proxy }
+}
+
+// DialTimeout configures the FaunaClient structure to limit the time spent establishing connections to FaunaDB.
+// Like Proxy, it is only used by the http.Client created by the driver.
+func DialTimeout(timeout time.Duration) ClientConfig {
+	return func(cli *FaunaClient) { cli.dialTimeout = timeout }
+}
+
+// TLSHandshakeTimeout configures the FaunaClient structure to limit the time spent in TLS handshakes with FaunaDB.
+// Like Proxy, it is only used by the http.Client created by the driver.
+func TLSHandshakeTimeout(timeout time.Duration) ClientConfig {
+	return func(cli *FaunaClient) { cli.tlsHandshakeTimeout = timeout }
+}
+
+// TLSConfig configures the FaunaClient structure to use the TLS configuration informed when connecting to FaunaDB,
+// for example to present a client certificate to a proxy requiring mutual TLS.
+// Like Proxy, it is only used by the http.Client created by the driver.
+func TLSConfig(config *tls.Config) ClientConfig {
+	return func(cli *FaunaClient) { cli.tlsConfig = config }
+}
+
+// ResponseHeaderTimeout configures the FaunaClient structure to limit the time spent waiting for the response
+// headers after a request is sent, not including the time spent reading the response body.
+// Like Proxy, it is only used by the http.Client created by the driver.
+func ResponseHeaderTimeout(timeout time.Duration) ClientConfig {
+	return func(cli *FaunaClient) { cli.responseHeaderTimeout = timeout }
+}
+
+// Timeout configures the FaunaClient structure to give up on requests that take longer than the duration informed.
+// It takes precedence over the timeout of a http.Client provided with the HTTP configuration: such client is copied
+// with the new timeout, leaving the original untouched. A zero duration means no timeout.
+func Timeout(timeout time.Duration) ClientConfig {
+	return func(cli *FaunaClient) { cli.timeout = &timeout }
+}
+
+// Auth configures the FaunaClient structure to build its Authorization header with a specific AuthScheme.
+func Auth(scheme AuthScheme) ClientConfig { return func(cli *FaunaClient) { cli.authScheme = scheme } }
+
+// AuthScheme builds the value of the Authorization header sent to FaunaDB from the secret informed.
+type AuthScheme func(secret string) string
+
+// BasicAuth encodes the secret as the username of a basic authentication header.
+// This is the default AuthScheme.
+func BasicAuth(secret string) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(secret))
+	return fmt.Sprintf("Basic %s:", encoded)
+}
+
+// BearerAuth sends the secret as a bearer token.
+func BearerAuth(secret string) string { return fmt.Sprintf("Bearer %s", secret) }
+
+// RequestDecorator configures the FaunaClient structure to call the function informed with every request, after its
+// standard headers are set and before it is sent. The function can modify the request arbitrarily, for example to
+// sign it. If the function returns an error, the query is aborted with that error.
+func RequestDecorator(decorator func(*http.Request) error) ClientConfig {
+	return func(cli *FaunaClient) { cli.decorateRequest = decorator }
+}
+
+// DefaultPageSize configures the FaunaClient structure to fetch pages of the size informed in the paginators created
+// by AllDocuments and PaginateSet. A Size optional parameter informed to a paginator takes precedence.
+func DefaultPageSize(size int) ClientConfig {
+	return func(cli *FaunaClient) { cli.pageSize = size }
+}
+
+// RequestIDFunc configures the FaunaClient structure to generate the ID of each request with the function informed.
+// Request IDs are sent in the X-Request-Id header and returned by QueryWithResult.
+func RequestIDFunc(fn func() string) ClientConfig {
+	return func(cli *FaunaClient) { cli.requestID = fn }
+}
+
+// MaxResponseBytes configures the FaunaClient structure to refuse responses whose body is larger than the limit
+// informed, returning a ResponseTooLargeError instead of loading them into memory.
+func MaxResponseBytes(limit int64) ClientConfig {
+	return func(cli *FaunaClient) { cli.maxResponseBytes = limit }
+}
+
+// Clock configures the FaunaClient structure to read the current local time from the function informed.
+// It is used wherever the driver measures time, such as the elapsed time of queries.
+func Clock(now func() time.Time) ClientConfig {
+	return func(cli *FaunaClient) { cli.clock = now }
+}
+
+// DefaultTags configures the FaunaClient structure to label every query with the tags informed.
+// Tags informed with the Tags query configuration take precedence over default tags with the same key.
+func DefaultTags(tags map[string]string) ClientConfig {
+	return func(cli *FaunaClient) { cli.tags = tags }
+}
+
+// QueryResult describes the value returned by a query along with metadata about its request.
+type QueryResult struct {
+	Value     Value  // Value returned by the query
+	RequestID string // ID sent in the X-Request-Id header
+	ReadOps   int64  // Read operations reported by the server in the X-Byte-Read-Ops header
+	WriteOps  int64  // Write operations reported by the server in the X-Byte-Write-Ops header
+
+	// Elapsed is the time spent between sending the request and parsing its response, measured with the client's Clock.
+	Elapsed time.Duration
+
+	// ReadOnly is true when the server reported that the query performed no write operations.
+	// It is false when the server omits the write operations header, as the query may have written.
+	ReadOnly bool
+}
+
+// QueryConfig are used to apply specific configurations to a single query.
+type QueryConfig func(*queryConfig)
+
+type queryConfig struct {
+	consistency    string
+	tags           map[string]string
+	dryRun         bool
+	idempotencyKey string
+	unwrapData     bool
+	fql            bool
+}
+
+/*
+Consistency configures the read consistency level of a query. Eventually consistent reads are served by the
+nearest replica without coordinating with the rest of the cluster, trading staleness for throughput.
+
+Only reads are affected: the level is sent in the X-Fauna-Read-Consistency header of queries that do not call any
+function that writes, such as Create, Update, Replace, Delete, Insert, Remove, Login, and Logout. Queries calling
+user-defined functions with Call, raw queries sent with QueryRaw, and FQL queries may write, so they are always sent
+without the header.
+*/
+func Consistency(level string) QueryConfig { return func(cfg *queryConfig) { cfg.consistency = level } }
+
+/*
+Tags configures the tags sent with a query in the X-Fauna-Tags header, used to label queries in FaunaDB metrics.
+Keys must have up to 40 characters and values up to 80 characters; both must be composed of letters, digits and
+underscores only. A query may have up to 25 tags. Queries with invalid tags are not sent.
+*/
+func Tags(tags map[string]string) QueryConfig {
+	return func(cfg *queryConfig) {
+		if cfg.tags == nil {
+			cfg.tags = make(map[string]string, len(tags))
+		}
+
+		for key, value := range tags {
+			cfg.tags[key] = value
+		}
+	}
+}
+
+// IdempotencyKey configures the key sent with a query in the Idempotency-Key header, allowing a proxy or server to
+// deduplicate repeated requests. The same key is sent on every attempt when the query is retried. See Retries.
+func IdempotencyKey(key string) QueryConfig {
+	return func(cfg *queryConfig) { cfg.idempotencyKey = key }
+}
+
+// UnwrapData configures a query to return the "data" field of its result, such as the data of an instance returned
+// by Get, instead of the whole result. Results that are not objects with a "data" field are returned unchanged.
+func UnwrapData() QueryConfig { return func(cfg *queryConfig) { cfg.unwrapData = true } }
+
+// DryRun configures a query to not be sent to FaunaDB. Instead, the query returns the exact JSON body it would
+// have sent as a BytesV value. See QueryExplain.
+func DryRun() QueryConfig { return func(cfg *queryConfig) { cfg.dryRun = true } }
+
+func newQueryConfig(configs []QueryConfig) *queryConfig {
+	cfg := &queryConfig{}
+
+	for _, config := range configs {
+		config(cfg)
+	}
+
+	return cfg
+}
+
 /*
 FaunaClient provides methods for performing queries on a FaunaDB cluster.
 
 This structure should be reused as much as possible. Avoid copying this structure.
 If you need to create a client with a different secret, use the NewSessionClient method.
+
+FaunaClient is safe for concurrent use by multiple goroutines: its configurations are not modified after the client
+is created, and its secret, which can be replaced with SetSecret, is protected by a mutex.
 */
 type FaunaClient struct {
-	basicAuth string
-	endpoint  string
-	http      *http.Client
+	credentials           *credentials
+	secretProvider        func() (string, error)
+	secretTTL             time.Duration
+	authScheme            AuthScheme
+	endpoint              string
+	fqlEndpoint           string
+	http                  *http.Client
+	proxy                 func(*http.Request) (*url.URL, error)
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	tlsConfig             *tls.Config
+	responseHeaderTimeout time.Duration
+	timeout               *time.Duration
+	requestID             func() string
+	maxResponseBytes      int64
+	clock                 func() time.Time
+	tags                  map[string]string
+	decorateRequest       func(*http.Request) error
+	pageSize              int
+	retries               int
+	backoff               func(retry int) time.Duration
+	recorder              io.Writer
+	marshalHook           func(interface{}) ([]byte, error)
+	unmarshalHook         func([]byte, interface{}) error
+	cacheSize             *int
+	cache                 *queryCache
+	maxConcurrency        int
+	slots                 chan struct{}
 }
 
 /*
 NewFaunaClient creates a new FaunaClient structure. Possible configurations are:
 	Endpoint: sets a specific FaunaDB url. Default: https://db.fauna.com
 		HTTP: sets a specific http.Client. Default: a new net.Client with 60 seconds timeout.
+		Proxy: sets the proxy of the default http.Client. Default: http.ProxyFromEnvironment.
+		DialTimeout: sets the connection timeout of the default http.Client. Default: 30 seconds.
+		TLSHandshakeTimeout: sets the TLS handshake timeout of the default http.Client. Default: 10 seconds.
+		TLSConfig: sets the TLS configuration of the default http.Client. Default: Go's default TLS configuration.
+		ResponseHeaderTimeout: sets the response header timeout of the default http.Client. Default: no timeout.
+		Timeout: sets the timeout of requests, overriding the timeout of the http.Client. Default: 60 seconds.
+		Auth: sets a specific AuthScheme. Default: BasicAuth.
+		RequestIDFunc: sets a specific request ID generator. Default: random UUIDs.
+		MaxResponseBytes: sets the maximum size of response bodies. Default: unlimited.
+		Clock: sets a specific source of local time. Default: time.Now.
+		DefaultTags: sets the tags sent with every query. Default: no tags.
+		RequestDecorator: sets a function that modifies every request before it is sent. Default: none.
+		DefaultPageSize: sets the page size of the client's paginators. Default: the server's page size.
+		Retries: sets how many times queries failed with transient errors are retried. Default: no retries.
+		QueryCacheSize: sets the maximum number of results cached by QueryCached. Default: 1000.
+		FQLEndpoint: sets the url to which QueryFQL sends FQL queries. Default: none, disabling QueryFQL.
+		MaxConcurrency: sets the maximum number of requests sent at the same time. Default: unlimited.
+		SecretProvider: sets a function fetching the secret, replacing the secret informed. Default: none.
+		Recorder: sets a writer to record the requests and responses exchanged with FaunaDB. Default: none.
+		JSONHooks: sets the functions encoding requests and decoding responses. Default: encoding/json.
 */
 func NewFaunaClient(secret string, configs ...ClientConfig) *FaunaClient {
-	client := &FaunaClient{basicAuth: basicAuth(secret)}
+	client := &FaunaClient{}
 
 	for _, config := range configs {
 		config(client)
 	}
 
+	if client.authScheme == nil {
+		client.authScheme = BasicAuth
+	}
+
+	client.credentials = newCredentials(client.authScheme, secret)
+
 	if client.endpoint == "" {
 		client.endpoint = defaultEndpoint
 	}
 
+	if client.proxy == nil {
+		client.proxy = http.ProxyFromEnvironment
+	}
+
 	if client.http == nil {
 		client.http = &http.Client{
-			Timeout: requestTimeout,
+			Transport: client.newTransport(),
+			Timeout:   requestTimeout,
 		}
 	}
 
+	if client.timeout != nil {
+		withTimeout := *client.http
+		withTimeout.Timeout = *client.timeout
+		client.http = &withTimeout
+	}
+
+	if client.recorder != nil {
+		recording := *client.http
+		recording.Transport = newRecordingTransport(recording.Transport, client.recorder)
+		client.http = &recording
+	}
+
+	if client.requestID == nil {
+		client.requestID = randomRequestID
+	}
+
+	if client.clock == nil {
+		client.clock = time.Now
+	}
+
+	if client.secretProvider != nil {
+		client.credentials.source = &secretSource{fetch: client.secretProvider, ttl: client.secretTTL, now: client.clock}
+	}
+
+	cacheSize := defaultQueryCacheSize
+	if client.cacheSize != nil {
+		cacheSize = *client.cacheSize
+	}
+
+	client.cache = newQueryCache(cacheSize)
+
+	if client.maxConcurrency > 0 {
+		client.slots = make(chan struct{}, client.maxConcurrency)
+	}
+
 	return client
 }
 
-// Query sends a query language expression to FaunaDB
-func (client *FaunaClient) Query(expr Expr) (value Value, err error) {
-	response, err := client.performRequest(expr)
+// NewFaunaClientChecked creates a new FaunaClient structure as NewFaunaClient does, but validates its configurations
+// before returning it. The endpoint must be an absolute http or https url, for example: https://db.fauna.com.
+func NewFaunaClientChecked(secret string, configs ...ClientConfig) (*FaunaClient, error) {
+	client := NewFaunaClient(secret, configs...)
+
+	if err := validateEndpoint(client.endpoint); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func validateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+
+	if err != nil {
+		return fmt.Errorf("Invalid endpoint %q: %s", endpoint, err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("Invalid endpoint %q: Scheme must be http or https", endpoint)
+	}
+
+	if parsed.Host == "" {
+		return fmt.Errorf("Invalid endpoint %q: Host must not be empty", endpoint)
+	}
+
+	return nil
+}
+
+// Query sends a query language expression to FaunaDB. Possible configurations are:
+//
+//	Consistency: sets the read consistency level of the query. Default: serialized.
+//	Tags: sets the tags of the query, merged with the client's DefaultTags. Default: no tags.
+//	IdempotencyKey: sets the key used to deduplicate retried requests. Default: no key.
+//	UnwrapData: returns the "data" field of the result instead of the whole result. Default: false.
+//	DryRun: returns the JSON body of the query as a BytesV value without sending it. Default: false.
+func (client *FaunaClient) Query(expr Expr, configs ...QueryConfig) (value Value, err error) {
+	var res QueryResult
+
+	if res, err = client.QueryWithResult(expr, configs...); err == nil {
+		value = res.Value
+	}
+
+	return
+}
+
+// QueryWithResult sends a query language expression to FaunaDB, returning its value along with metadata
+// about the request. It accepts the same configurations as Query.
+func (client *FaunaClient) QueryWithResult(expr Expr, configs ...QueryConfig) (result QueryResult, err error) {
+	cfg := newQueryConfig(configs)
+
+	if cfg.dryRun {
+		var request *http.Request
+		var body []byte
+
+		if request, err = client.prepareRequest(expr, cfg); err == nil {
+			if body, err = ioutil.ReadAll(request.Body); err == nil {
+				result.Value = BytesV(body)
+			}
+		}
+
+		return
+	}
+
+	start := client.clock()
+	defer func() { result.Elapsed = client.clock().Sub(start) }()
+
+	for retry := 1; ; retry++ {
+		result, err = client.send(expr, cfg)
+
+		if err == nil || retry > client.retries || !isTransientError(err) {
+			return
+		}
+
+		if client.backoff != nil {
+			time.Sleep(client.backoff(retry))
+		}
+	}
+}
+
+func (client *FaunaClient) send(expr Expr, cfg *queryConfig) (result QueryResult, err error) {
+	var request *http.Request
+	var response *http.Response
+
+	if request, err = client.prepareRequest(expr, cfg); err != nil {
+		return
+	}
+
+	if err = client.acquire(); err != nil {
+		return
+	}
+
+	defer client.release()
+
+	result.RequestID = request.Header.Get(requestIDHeader)
+	response, err = client.http.Do(request)
 
 	if response != nil {
 		defer func() {
-			_, _ = io.Copy(ioutil.Discard, response.Body) // Discard remaining bytes so the connection can be reused
+			// Discard a few remaining bytes so the connection can be reused, without downloading large bodies
+			_, _ = io.CopyN(ioutil.Discard, response.Body, maxDrainBytes)
 			_ = response.Body.Close()
 		}()
 	}
 
 	if err == nil {
 		if err = checkForResponseErrors(response); err == nil {
-			value, err = client.parseResponse(response)
+			result.readOps(response.Header)
+			envelope := resource
+			if cfg.fql {
+				envelope = dataField
+			}
+
+			result.Value, err = client.parseResponse(response, envelope)
+
+			if err == nil && cfg.unwrapData {
+				if data, dataErr := result.Value.At(dataField).GetValue(); dataErr == nil {
+					result.Value = data
+				}
+			}
+		}
+	}
+
+	return
+}
+
+func (result *QueryResult) readOps(header http.Header) {
+	result.ReadOps, _ = strconv.ParseInt(header.Get(readOpsHeader), 10, 64)
+
+	if writeOps := header.Get(writeOpsHeader); writeOps != "" {
+		if ops, err := strconv.ParseInt(writeOps, 10, 64); err == nil {
+			result.WriteOps = ops
+			result.ReadOnly = ops == 0
 		}
 	}
+}
+
+// QueryRaw sends a pre-serialized query language expression to FaunaDB, bypassing expressions encoding.
+// The body informed must be a non-empty JSON document.
+func (client *FaunaClient) QueryRaw(body []byte, configs ...QueryConfig) (value Value, err error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, errEmptyRawQuery
+	}
+
+	return client.Query(rawExpr(body), configs...)
+}
+
+// QueryExplain returns the exact JSON body that would be sent to FaunaDB for the query language expression informed,
+// without sending it. It accepts the same configurations as Query, validating them as if the query was sent.
+func (client *FaunaClient) QueryExplain(expr Expr, configs ...QueryConfig) (body []byte, err error) {
+	var value Value
+
+	if value, err = client.Query(expr, append(configs, DryRun())...); err == nil {
+		body = value.(BytesV)
+	}
 
 	return
 }
 
 // BatchQuery sends multiple query language expressions to FaunaDB
-func (client *FaunaClient) BatchQuery(exprs []Expr) (values []Value, err error) {
+func (client *FaunaClient) BatchQuery(exprs []Expr, configs ...QueryConfig) (values []Value, err error) {
 	arr := make(unescapedArr, len(exprs))
 
 	for i, expr := range exprs {
@@ -94,56 +526,462 @@ func (client *FaunaClient) BatchQuery(exprs []Expr) (values []Value, err error)
 
 	var res Value
 
-	if res, err = client.Query(arr); err == nil {
+	if res, err = client.Query(arr, configs...); err == nil {
 		err = res.Get(&values)
 	}
 
 	return
 }
 
+// GetField retrieves a single field of the instance identified by the ref informed, decoding it into the target.
+// The field is selected on the server, avoiding the transfer of the whole instance. The path informed is composed
+// of object keys and array indexes, for example: []interface{}{"data", "tags", 0}.
+func (client *FaunaClient) GetField(ref interface{}, path []interface{}, target interface{}, configs ...QueryConfig) (err error) {
+	var res Value
+
+	if res, err = client.Query(Select(path, Get(ref)), configs...); err == nil {
+		err = res.Get(target)
+	}
+
+	return
+}
+
+// GetAll retrieves the instances identified by the refs informed in a single query. The values returned are in the
+// same order as the refs, with NullV in place of the instances that do not exist.
+func (client *FaunaClient) GetAll(refs []RefV, configs ...QueryConfig) (values []Value, err error) {
+	var res Value
+
+	getOrNull := Lambda("ref", If(Exists(Var("ref")), Get(Var("ref")), NullV{}))
+
+	if res, err = client.Query(Map(refs, getOrNull), configs...); err == nil {
+		var arr ArrayV
+
+		if err = res.Get(&arr); err == nil {
+			values = arr
+		}
+	}
+
+	return
+}
+
+// Upsert updates the instance identified by the ref informed if it exists, otherwise creates it.
+// It returns the resulting instance. See the Upsert function for details.
+func (client *FaunaClient) Upsert(ref, params interface{}, configs ...QueryConfig) (Value, error) {
+	return client.Query(Upsert(ref, params), configs...)
+}
+
+// CreateAndGet creates an instance of the class informed with the data informed, usually a struct, and decodes
+// the data of the created instance into the target.
+func (client *FaunaClient) CreateAndGet(classRef, data, target interface{}, configs ...QueryConfig) (err error) {
+	var res Value
+
+	if res, err = client.Query(Create(classRef, Obj{"data": data}), configs...); err == nil {
+		err = res.At(dataField).Get(target)
+	}
+
+	return
+}
+
+// UpdateReturning updates the instance identified by the ref informed with the data informed, usually a struct,
+// and decodes the data of the updated instance, including the fields not informed, into the target.
+func (client *FaunaClient) UpdateReturning(ref, data, target interface{}, configs ...QueryConfig) (err error) {
+	var res Value
+
+	if res, err = client.Query(Update(ref, Obj{"data": data}), configs...); err == nil {
+		err = res.At(dataField).Get(target)
+	}
+
+	return
+}
+
+// CollectionExists checks if a collection with the name informed exists in the client's database.
+func (client *FaunaClient) CollectionExists(name string, configs ...QueryConfig) (bool, error) {
+	return client.exists(Collection(name), configs)
+}
+
+// IndexExists checks if an index with the name informed exists in the client's database.
+func (client *FaunaClient) IndexExists(name string, configs ...QueryConfig) (bool, error) {
+	return client.exists(Index(name), configs)
+}
+
+// EnsureCollection creates a collection with the name and parameters informed unless it already exists, checking and
+// creating it in a single query. It returns true if the collection was created. The name informed takes precedence
+// over a name in the parameters.
+func (client *FaunaClient) EnsureCollection(name string, params Obj, configs ...QueryConfig) (bool, error) {
+	return client.ensure(Collection(name), CreateCollection(withName(params, name)), configs)
+}
+
+// EnsureIndex creates an index with the name and parameters informed unless it already exists, as EnsureCollection
+// does for collections.
+func (client *FaunaClient) EnsureIndex(name string, params Obj, configs ...QueryConfig) (bool, error) {
+	return client.ensure(Index(name), CreateIndex(withName(params, name)), configs)
+}
+
+// EnsureFunction creates a user defined function with the name and parameters informed unless it already exists,
+// as EnsureCollection does for collections.
+func (client *FaunaClient) EnsureFunction(name string, params Obj, configs ...QueryConfig) (bool, error) {
+	return client.ensure(Function(name), CreateFunction(withName(params, name)), configs)
+}
+
+// KeyResult describes a key created by the CreateKey method. Role is a StringV for built-in roles, such as "server",
+// or a RefV for user defined roles. Database is the zero RefV for keys of the client's own database.
+type KeyResult struct {
+	Ref      RefV   `fauna:"ref"`
+	Secret   string `fauna:"secret"`
+	Role     Value  `fauna:"role"`
+	Database RefV   `fauna:"database"`
+}
+
+/*
+CreateKey creates a key with the parameters informed and decodes the result, including its secret. FaunaDB returns
+the secret of a key only once, when it is created, so it must be stored by the caller. For example, to access a child
+database:
+
+	key, err := client.CreateKey(Obj{"database": Database("tenant"), "role": "server"})
+	if err != nil {
+		panic(err)
+	}
+
+	tenant := client.NewSessionClient(key.Secret)
+*/
+func (client *FaunaClient) CreateKey(params Obj, configs ...QueryConfig) (key KeyResult, err error) {
+	var res Value
+
+	if res, err = client.Query(CreateKey(params), configs...); err == nil {
+		err = res.Get(&key)
+	}
+
+	return
+}
+
+func (client *FaunaClient) ensure(ref, create Expr, configs []QueryConfig) (created bool, err error) {
+	var res Value
+
+	if res, err = client.Query(CreateIfAbsent(ref, create), configs...); err == nil {
+		err = res.Get(&created)
+	}
+
+	return
+}
+
+func withName(params Obj, name string) Obj {
+	named := make(Obj, len(params)+1)
+
+	for key, value := range params {
+		named[key] = value
+	}
+
+	named["name"] = name
+	return named
+}
+
+func (client *FaunaClient) exists(ref Expr, configs []QueryConfig) (exists bool, err error) {
+	var res Value
+
+	if res, err = client.Query(Exists(ref), configs...); err == nil {
+		err = res.Get(&exists)
+	}
+
+	return
+}
+
 // NewSessionClient creates a new child FaunaClient with the specified secret. The new client reuses its parents internal http resources.
 func (client *FaunaClient) NewSessionClient(secret string) *FaunaClient {
-	return &FaunaClient{
-		basicAuth: basicAuth(secret),
-		endpoint:  client.endpoint,
-		http:      client.http,
+	session := *client
+	session.credentials = newCredentials(client.authScheme, secret)
+	session.cache = newQueryCache(client.cache.size)
+
+	return &session
+}
+
+// SetSecret replaces the secret used by the client. Queries sent after SetSecret returns use the new secret,
+// while queries already sent are not affected. Session clients created from this client keep their own secrets.
+// It also discards the results cached by QueryCached, as they may not be visible with the new secret.
+func (client *FaunaClient) SetSecret(secret string) {
+	client.credentials.set(client.authScheme, secret)
+	client.cache.clear()
+}
+
+// ScopedQuery sends a query language expression to FaunaDB scoped into the child database informed, as if it was sent
+// with an admin key of that database. The database name may be a path to a nested database, such as "tenants/acme".
+// It accepts the same configurations as Query.
+func (client *FaunaClient) ScopedQuery(database string, expr Expr, configs ...QueryConfig) (Value, error) {
+	if err := client.credentials.refresh(client.authScheme); err != nil {
+		return nil, err
 	}
+
+	scoped := client.NewSessionClient(fmt.Sprintf("%s:%s:admin", client.credentials.secret(), database))
+	return scoped.Query(expr, configs...)
 }
 
-func (client *FaunaClient) performRequest(expr Expr) (response *http.Response, err error) {
-	var request *http.Request
+// newTransport creates the transport of the default http.Client, with the settings of http.DefaultTransport
+// unless configured otherwise.
+func (client *FaunaClient) newTransport() *http.Transport {
+	transport := defaultTransport()
+	transport.Proxy = client.proxy
+	transport.ResponseHeaderTimeout = client.responseHeaderTimeout
 
-	if request, err = client.prepareRequest(expr); err == nil {
-		response, err = client.http.Do(request)
+	if client.tlsConfig != nil {
+		transport.TLSClientConfig = client.tlsConfig
 	}
 
-	return
+	if client.tlsHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = client.tlsHandshakeTimeout
+	}
+
+	if client.dialTimeout > 0 {
+		setDialTimeout(transport, client.dialTimeout)
+	}
+
+	return transport
 }
 
-func (client *FaunaClient) prepareRequest(expr Expr) (request *http.Request, err error) {
+func (client *FaunaClient) prepareRequest(expr Expr, cfg *queryConfig) (request *http.Request, err error) {
 	var body []byte
+	var tags string
+
+	if tags, err = encodeTags(client.tags, cfg.tags); err != nil {
+		return
+	}
 
-	if body, err = json.Marshal(expr); err == nil {
-		if request, err = http.NewRequest("POST", client.endpoint, bytes.NewReader(body)); err == nil {
-			request.Header.Add("Authorization", client.basicAuth)
+	if err = client.credentials.refresh(client.authScheme); err != nil {
+		return
+	}
+
+	endpoint := client.endpoint
+	if cfg.fql {
+		endpoint = client.fqlEndpoint
+	}
+
+	if body, err = client.marshalRequest(expr); err == nil {
+		if request, err = http.NewRequest("POST", endpoint, bytes.NewReader(body)); err == nil {
+			request.Header.Add("Authorization", client.credentials.authHeader())
 			request.Header.Add("Content-Type", "application/json; charset=utf-8")
+			request.Header.Add(requestIDHeader, client.requestID())
+
+			if cfg.consistency != "" && !cfg.fql && isReadQuery(expr) {
+				request.Header.Add(consistencyHeader, cfg.consistency)
+			}
+
+			if tags != "" {
+				request.Header.Add(tagsHeader, tags)
+			}
+
+			if cfg.idempotencyKey != "" {
+				request.Header.Add(idempotencyKeyHeader, cfg.idempotencyKey)
+			}
+
+			if client.decorateRequest != nil {
+				if err = client.decorateRequest(request); err != nil {
+					request = nil
+				}
+			}
 		}
 	}
 
 	return
 }
 
-func (client *FaunaClient) parseResponse(response *http.Response) (Value, error) {
-	value, err := parseJSON(response.Body)
+// Functions that write, or may write, as with user-defined functions called with Call.
+var writeFunctions = map[string]bool{
+	"create": true, "create_class": true, "create_collection": true, "create_database": true,
+	"create_function": true, "create_index": true, "create_key": true, "create_role": true,
+	"insert": true, "update": true, "replace": true, "delete": true, "remove": true,
+	"login": true, "logout": true, "call": true,
+}
 
-	if err != nil {
-		return nil, err
+// isReadQuery reports whether the expression informed does not call any of the writeFunctions. Object literals and
+// the bindings of Let are checked by their values only, as their keys are not function names. Raw expressions can not
+// be checked, so they are reported as writes.
+func isReadQuery(expr Expr) bool {
+	switch e := expr.(type) {
+	case Obj, Arr:
+		return isReadQuery(wrap(e))
+	case unescapedArr:
+		for _, elem := range e {
+			if !isReadQuery(elem) {
+				return false
+			}
+		}
+	case unescapedObj:
+		if literal, ok := e["object"].(unescapedObj); ok && len(e) == 1 {
+			return valuesAreReads(literal)
+		}
+
+		for key, value := range e {
+			if writeFunctions[key] {
+				return false
+			}
+
+			if bindings, ok := value.(unescapedObj); ok && key == "let" {
+				if !valuesAreReads(bindings) {
+					return false
+				}
+			} else if !isReadQuery(value) {
+				return false
+			}
+		}
+	case rawExpr, invalidExpr:
+		return false
 	}
 
-	return value.At(resource).GetValue()
+	return true
 }
 
-func basicAuth(secret string) string {
-	encoded := base64.StdEncoding.EncodeToString([]byte(secret))
-	return fmt.Sprintf("Basic %s:", encoded)
+func valuesAreReads(obj unescapedObj) bool {
+	for _, value := range obj {
+		if !isReadQuery(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (client *FaunaClient) parseResponse(response *http.Response, envelope Field) (value Value, err error) {
+	var body io.Reader = response.Body
+
+	if client.maxResponseBytes > 0 {
+		var limited []byte
+
+		if limited, err = ioutil.ReadAll(io.LimitReader(response.Body, client.maxResponseBytes+1)); err != nil {
+			return
+		}
+
+		if int64(len(limited)) > client.maxResponseBytes {
+			return nil, ResponseTooLargeError{Limit: client.maxResponseBytes}
+		}
+
+		body = bytes.NewReader(limited)
+	}
+
+	if client.unmarshalHook != nil {
+		value, err = client.unmarshalEnvelope(body, envelope)
+	} else {
+		value, err = parseEnvelope(body, envelope)
+	}
+
+	switch err.(type) {
+	case nil:
+	case *json.SyntaxError:
+		err = InvalidResponseError{Status: response.StatusCode, Cause: err}
+	case invalidJSONError:
+		err = InvalidResponseError{Status: response.StatusCode, Cause: err.(invalidJSONError).cause}
+	default:
+		if err == io.EOF {
+			err = EmptyResponseError{Status: response.StatusCode}
+		}
+	}
+
+	return
+}
+
+const (
+	maxTags           = 25
+	maxTagKeyLength   = 40
+	maxTagValueLength = 80
+)
+
+// encodeTags merges the tags informed, from lowest to highest precedence, into the "key=value,key=value"
+// format expected by the X-Fauna-Tags header. Keys are sorted so the header is deterministic.
+func encodeTags(tagSets ...map[string]string) (string, error) {
+	merged := make(map[string]string)
+
+	for _, tags := range tagSets {
+		for key, value := range tags {
+			merged[key] = value
+		}
+	}
+
+	if len(merged) > maxTags {
+		return "", fmt.Errorf("Invalid query tags: Expected at most %d tags but got %d", maxTags, len(merged))
+	}
+
+	keys := make([]string, 0, len(merged))
+
+	for key, value := range merged {
+		if !validTag(key, maxTagKeyLength) {
+			return "", fmt.Errorf("Invalid query tag key %q: Expected up to %d letters, digits or underscores", key, maxTagKeyLength)
+		}
+
+		if !validTag(value, maxTagValueLength) {
+			return "", fmt.Errorf("Invalid query tag value %q: Expected up to %d letters, digits or underscores", value, maxTagValueLength)
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+
+	for i, key := range keys {
+		pairs[i] = key + "=" + merged[key]
+	}
+
+	return strings.Join(pairs, ","), nil
+}
+
+func validTag(str string, maxLength int) bool {
+	if str == "" || len(str) > maxLength {
+		return false
+	}
+
+	for _, char := range str {
+		if !(char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' || char == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// credentials holds the secret of a client along with its Authorization header, allowing the
+// secret to be replaced while queries are sent concurrently.
+type credentials struct {
+	mutex  sync.RWMutex
+	key    string
+	header string
+	source *secretSource // Set when the secret is fetched from a SecretProvider
+}
+
+func newCredentials(scheme AuthScheme, secret string) *credentials {
+	return &credentials{key: secret, header: scheme(secret)}
+}
+
+func (c *credentials) set(scheme AuthScheme, secret string) {
+	header := scheme(secret)
+
+	c.mutex.Lock()
+	c.key, c.header = secret, header
+
+	if c.source != nil {
+		c.source.expires = c.source.now().Add(c.source.ttl)
+	}
+
+	c.mutex.Unlock()
+}
+
+func (c *credentials) secret() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.key
+}
+
+func (c *credentials) authHeader() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.header
+}
+
+func randomRequestID() string {
+	var uuid [16]byte
+
+	_, _ = rand.Read(uuid[:])
+	uuid[6] = (uuid[6] & 0x0f) | 0x40 // Version 4
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
 }
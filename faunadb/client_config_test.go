@@ -0,0 +1,1096 @@
+package faunadb
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasicAuthScheme(t *testing.T) {
+	require.Equal(t, "Basic c2VjcmV0:", BasicAuth("secret"))
+}
+
+func TestBearerAuthScheme(t *testing.T) {
+	require.Equal(t, "Bearer secret", BearerAuth("secret"))
+}
+
+func TestUseBasicAuthByDefault(t *testing.T) {
+	client := NewFaunaClient("secret")
+
+	request, err := client.prepareRequest(NullV{}, newQueryConfig(nil))
+	require.NoError(t, err)
+	require.Equal(t, "Basic c2VjcmV0:", request.Header.Get("Authorization"))
+}
+
+func TestUseConfiguredAuthScheme(t *testing.T) {
+	client := NewFaunaClient("secret", Auth(BearerAuth))
+
+	request, err := client.prepareRequest(NullV{}, newQueryConfig(nil))
+	require.NoError(t, err)
+	require.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+}
+
+func TestSessionClientKeepsAuthScheme(t *testing.T) {
+	client := NewFaunaClient("secret", Auth(BearerAuth)).NewSessionClient("session-secret")
+
+	request, err := client.prepareRequest(NullV{}, newQueryConfig(nil))
+	require.NoError(t, err)
+	require.Equal(t, "Bearer session-secret", request.Header.Get("Authorization"))
+}
+
+func TestDoNotSetConsistencyByDefault(t *testing.T) {
+	client := NewFaunaClient("secret")
+
+	request, err := client.prepareRequest(Get(Ref("classes/spells/42")), newQueryConfig(nil))
+	require.NoError(t, err)
+	require.Empty(t, request.Header.Get(consistencyHeader))
+}
+
+func TestSetConsistencyForQuery(t *testing.T) {
+	client := NewFaunaClient("secret")
+
+	request, err := client.prepareRequest(
+		Get(Ref("classes/spells/42")),
+		newQueryConfig([]QueryConfig{Consistency(ConsistencyEventual)}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "eventual", request.Header.Get(consistencyHeader))
+}
+
+func TestSendConsistencyForReadsOnly(t *testing.T) {
+	client := NewFaunaClient("secret")
+
+	reads := []Expr{
+		Get(Ref("classes/spells/42")),
+		Paginate(MatchTerm(Index("spells_by_element"), "fire")),
+		Obj{"create": Get(Ref("classes/spells/42")), "update": true},
+		Let(Obj{"delete": Get(Ref("classes/spells/42"))}, Var("delete")),
+		LetFn(Ref("classes/spells/42"), func(ref BoundVar) Expr { return Exists(ref) }),
+		Arr{1, Select(Arr{"data", "name"}, Get(Ref("classes/spells/42")))},
+	}
+
+	writes := []Expr{
+		Create(Class("spells"), Obj{}),
+		Update(Ref("classes/spells/42"), Obj{"data": Obj{"name": "Fire"}}),
+		Do(Get(Ref("classes/spells/42")), Delete(Ref("classes/spells/42"))),
+		If(true, Replace(Ref("classes/spells/42"), Obj{}), NullV{}),
+		Obj{"spell": Create(Class("spells"), Obj{})},
+		Let(Obj{"spell": Create(Class("spells"), Obj{})}, Var("spell")),
+		Call(Function("cast"), 1),
+		Login(Ref("classes/users/1"), Obj{"password": "abracadabra"}),
+		rawExpr(`{"get":{"@ref":"classes/spells/42"}}`),
+	}
+
+	for _, expr := range reads {
+		request, err := client.prepareRequest(expr, newQueryConfig([]QueryConfig{Consistency(ConsistencyEventual)}))
+		require.NoError(t, err)
+		require.Equal(t, "eventual", request.Header.Get(consistencyHeader), "%#v", expr)
+	}
+
+	for _, expr := range writes {
+		request, err := client.prepareRequest(expr, newQueryConfig([]QueryConfig{Consistency(ConsistencyEventual)}))
+		require.NoError(t, err)
+		require.Empty(t, request.Header.Get(consistencyHeader), "%#v", expr)
+	}
+}
+
+func TestSendConsistencyOnReadQueries(t *testing.T) {
+	server := newMockServer(`{"resource": null}`, `{"resource": null}`, `{"resource": null}`, `{"data": null}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.Query(Get(Ref("classes/spells/42")), Consistency(ConsistencyEventual))
+	require.NoError(t, err)
+
+	_, err = client.Query(Create(Class("spells"), Obj{}), Consistency(ConsistencyEventual))
+	require.NoError(t, err)
+
+	_, err = client.QueryRaw([]byte(`{"get":{"@ref":"classes/spells/42"}}`), Consistency(ConsistencyEventual))
+	require.NoError(t, err)
+
+	_, err = server.client(FQLEndpoint(server.URL)).QueryFQL("Spells.all()", nil, Consistency(ConsistencyEventual))
+	require.NoError(t, err)
+
+	require.Equal(t, "eventual", server.requestHeader(0).Get(consistencyHeader))
+	require.Empty(t, server.requestHeader(1).Get(consistencyHeader))
+	require.Empty(t, server.requestHeader(2).Get(consistencyHeader))
+	require.Empty(t, server.requestHeader(3).Get(consistencyHeader))
+}
+
+func TestQueryRaw(t *testing.T) {
+	server := newMockServer(`{"resource": {"ref": {"@ref": "classes/spells/42"}}}`)
+	defer server.Close()
+
+	value, err := server.client().QueryRaw([]byte(`{ "get": { "@ref": "classes/spells/42" } }`))
+
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"ref": RefV{ID: "classes/spells/42"}}, value)
+	require.Equal(t, []string{`{"get":{"@ref":"classes/spells/42"}}`}, server.requestBodies())
+}
+
+func TestNotSendEmptyRawQuery(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	_, err := server.client().QueryRaw([]byte(" \n"))
+
+	require.EqualError(t, err, "Error while sending raw query: Query body must not be empty")
+	require.Empty(t, server.requestBodies())
+}
+
+func TestNotSendInvalidRawQuery(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	_, err := server.client().QueryRaw([]byte(`{"get": `))
+
+	require.Error(t, err)
+	require.Empty(t, server.requestBodies())
+}
+
+func TestSendRequestID(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	client := server.client(RequestIDFunc(func() string { return "my-request-id" }))
+
+	res, err := client.QueryWithResult(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, "my-request-id", res.RequestID)
+	require.Equal(t, "my-request-id", server.requestHeader(0).Get("X-Request-Id"))
+}
+
+func TestGenerateUniqueRequestIDs(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	client := server.client()
+	ids := make(map[string]bool)
+
+	for i := 0; i < 100; i++ {
+		res, err := client.QueryWithResult(NullV{})
+		require.NoError(t, err)
+		require.Regexp(t, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", res.RequestID)
+
+		ids[res.RequestID] = true
+	}
+
+	require.Len(t, ids, 100)
+}
+
+func TestRejectResponsesLargerThanLimit(t *testing.T) {
+	server := newMockServer(`{"resource": "` + strings.Repeat("a", 1024) + `"}`)
+	defer server.Close()
+
+	client := server.client(MaxResponseBytes(512))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Query(NullV{})
+		require.Equal(t, ResponseTooLargeError{Limit: 512}, err)
+	}
+
+	require.Len(t, server.requestBodies(), 2)
+}
+
+// endlessBody is a response body that never ends, counting the bytes read from it.
+type endlessBody struct {
+	read   int64
+	closed bool
+}
+
+func (body *endlessBody) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+
+	body.read += int64(len(p))
+	return len(p), nil
+}
+
+func (body *endlessBody) Close() error {
+	body.closed = true
+	return nil
+}
+
+func TestStopReadingResponsesLargerThanLimit(t *testing.T) {
+	body := &endlessBody{}
+
+	transport := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body, Request: request}, nil
+	})
+
+	_, err := NewFaunaClientWithTransport("secret", transport, MaxResponseBytes(512)).Query(NullV{})
+	require.Equal(t, ResponseTooLargeError{Limit: 512}, err)
+	require.True(t, body.read <= 513+maxDrainBytes, "read %d bytes", body.read)
+	require.True(t, body.closed)
+}
+
+func TestAcceptResponsesWithinLimit(t *testing.T) {
+	server := newMockServer(`{"resource": "spell"}`)
+	defer server.Close()
+
+	value, err := server.client(MaxResponseBytes(int64(len(`{"resource": "spell"}`)))).Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, StringV("spell"), value)
+}
+
+func TestReportReadOnlyQueries(t *testing.T) {
+	headers := http.Header{"X-Byte-Read-Ops": {"3"}, "X-Byte-Write-Ops": {"0"}}
+	server := newMockServerWithHeaders(headers, `{"resource": null}`)
+	defer server.Close()
+
+	res, err := server.client().QueryWithResult(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), res.ReadOps)
+	require.Equal(t, int64(0), res.WriteOps)
+	require.True(t, res.ReadOnly)
+}
+
+func TestReportWriteQueries(t *testing.T) {
+	headers := http.Header{"X-Byte-Read-Ops": {"1"}, "X-Byte-Write-Ops": {"2"}}
+	server := newMockServerWithHeaders(headers, `{"resource": null}`)
+	defer server.Close()
+
+	res, err := server.client().QueryWithResult(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.ReadOps)
+	require.Equal(t, int64(2), res.WriteOps)
+	require.False(t, res.ReadOnly)
+}
+
+func TestDoNotAssumeReadOnlyWithoutOpsHeaders(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	res, err := server.client().QueryWithResult(NullV{})
+	require.NoError(t, err)
+	require.False(t, res.ReadOnly)
+}
+
+func TestMeasureElapsedTimeWithClock(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	now := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+
+	client := server.client(Clock(clock))
+
+	res, err := client.QueryWithResult(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, res.Elapsed)
+
+	now = now.Add(time.Hour)
+
+	res, err = client.QueryWithResult(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, res.Elapsed)
+}
+
+func TestMeasureElapsedTimeOnFailedQueries(t *testing.T) {
+	now := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	client := NewFaunaClient("secret", Endpoint("http://127.0.0.1:1"), Clock(clock))
+
+	res, err := client.QueryWithResult(NullV{})
+	require.Error(t, err)
+	require.Equal(t, time.Second, res.Elapsed)
+}
+
+func TestCheckedClientAcceptsValidEndpoints(t *testing.T) {
+	for _, endpoint := range []string{"https://db.fauna.com", "http://localhost:8443", "https://db.fauna.com/"} {
+		client, err := NewFaunaClientChecked("secret", Endpoint(endpoint))
+		require.NoError(t, err, endpoint)
+		require.Equal(t, endpoint, client.endpoint)
+	}
+
+	client, err := NewFaunaClientChecked("secret")
+	require.NoError(t, err)
+	require.Equal(t, "https://db.fauna.com", client.endpoint)
+}
+
+func TestCheckedClientRejectsMalformedEndpoints(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		message  string
+	}{
+		{"db.fauna.com", `Invalid endpoint "db.fauna.com": Scheme must be http or https`},
+		{"localhost:8443", `Invalid endpoint "localhost:8443": Scheme must be http or https`},
+		{"ftp://db.fauna.com", `Invalid endpoint "ftp://db.fauna.com": Scheme must be http or https`},
+		{"https://", `Invalid endpoint "https://": Host must not be empty`},
+		{"https:///path", `Invalid endpoint "https:///path": Host must not be empty`},
+	}
+
+	for _, test := range tests {
+		client, err := NewFaunaClientChecked("secret", Endpoint(test.endpoint))
+		require.Nil(t, client)
+		require.EqualError(t, err, test.message)
+	}
+
+	_, err := NewFaunaClientChecked("secret", Endpoint("https://db fauna.com"))
+	require.Error(t, err)
+}
+
+func TestGetField(t *testing.T) {
+	server := newMockServer(`{"resource": "Fire"}`)
+	defer server.Close()
+
+	var name string
+
+	err := server.client().GetField(Ref("classes/spells/42"), []interface{}{"data", "name"}, &name)
+	require.NoError(t, err)
+	require.Equal(t, "Fire", name)
+	require.Equal(t, []string{`{"from":{"get":{"@ref":"classes/spells/42"}},"select":["data","name"]}`}, server.requestBodies())
+}
+
+func TestGetFieldReportsDecodeErrors(t *testing.T) {
+	server := newMockServer(`{"resource": "Fire"}`)
+	defer server.Close()
+
+	var level int
+
+	err := server.client().GetField(Ref("classes/spells/42"), []interface{}{"data", "level"}, &level)
+	require.IsType(t, DecodeError{}, err)
+}
+
+func TestDoNotSendTagsByDefault(t *testing.T) {
+	client := NewFaunaClient("secret")
+
+	request, err := client.prepareRequest(NullV{}, newQueryConfig(nil))
+	require.NoError(t, err)
+	require.Empty(t, request.Header.Get("X-Fauna-Tags"))
+}
+
+func TestSendQueryTags(t *testing.T) {
+	client := NewFaunaClient("secret")
+
+	request, err := client.prepareRequest(NullV{}, newQueryConfig([]QueryConfig{
+		Tags(map[string]string{"feature": "search", "area": "spells_2"}),
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "area=spells_2,feature=search", request.Header.Get("X-Fauna-Tags"))
+}
+
+func TestMergeDefaultTagsWithQueryTags(t *testing.T) {
+	client := NewFaunaClient("secret", DefaultTags(map[string]string{"service": "api", "feature": "none"}))
+
+	request, err := client.prepareRequest(NullV{}, newQueryConfig(nil))
+	require.NoError(t, err)
+	require.Equal(t, "feature=none,service=api", request.Header.Get("X-Fauna-Tags"))
+
+	request, err = client.prepareRequest(NullV{}, newQueryConfig([]QueryConfig{
+		Tags(map[string]string{"feature": "search"}),
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "feature=search,service=api", request.Header.Get("X-Fauna-Tags"))
+}
+
+func TestRejectInvalidQueryTags(t *testing.T) {
+	tests := []struct {
+		tags    map[string]string
+		message string
+	}{
+		{map[string]string{"": "value"}, `Invalid query tag key "": Expected up to 40 letters, digits or underscores`},
+		{map[string]string{"my-key": "value"}, `Invalid query tag key "my-key": Expected up to 40 letters, digits or underscores`},
+		{map[string]string{"key": "a,b=c"}, `Invalid query tag value "a,b=c": Expected up to 80 letters, digits or underscores`},
+		{map[string]string{"key": "feitiço"}, `Invalid query tag value "feitiço": Expected up to 80 letters, digits or underscores`},
+		{map[string]string{strings.Repeat("k", 41): "value"}, `Invalid query tag key "` + strings.Repeat("k", 41) + `": Expected up to 40 letters, digits or underscores`},
+		{map[string]string{"key": strings.Repeat("v", 81)}, `Invalid query tag value "` + strings.Repeat("v", 81) + `": Expected up to 80 letters, digits or underscores`},
+	}
+
+	for _, test := range tests {
+		_, err := NewFaunaClient("secret").prepareRequest(NullV{}, newQueryConfig([]QueryConfig{Tags(test.tags)}))
+		require.EqualError(t, err, test.message)
+	}
+}
+
+func TestRejectTooManyQueryTags(t *testing.T) {
+	tags := make(map[string]string)
+
+	for i := 0; i < 26; i++ {
+		tags[fmt.Sprintf("key%d", i)] = "value"
+	}
+
+	_, err := NewFaunaClient("secret").prepareRequest(NullV{}, newQueryConfig([]QueryConfig{Tags(tags)}))
+	require.EqualError(t, err, "Invalid query tags: Expected at most 25 tags but got 26")
+}
+
+func TestNotSendQueriesWithInvalidTags(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	_, err := server.client().Query(NullV{}, Tags(map[string]string{"bad key": "value"}))
+	require.Error(t, err)
+	require.Empty(t, server.requestBodies())
+}
+
+func TestDoNotEscapeHTMLInQueries(t *testing.T) {
+	server := newMockServer(`{"resource": "<b>Fire & Ice</b>"}`)
+	defer server.Close()
+
+	value, err := server.client().Query(
+		Obj{"name": "<b>Fire & Ice</b>", "tags": Arr{"a<b"}, "ref": ObjectV{"x": StringV(">")}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, StringV("<b>Fire & Ice</b>"), value)
+	require.Equal(t,
+		[]string{`{"object":{"name":"<b>Fire & Ice</b>","ref":{"object":{"x":">"}},"tags":["a<b"]}}`},
+		server.requestBodies(),
+	)
+}
+
+func TestScopedQuery(t *testing.T) {
+	server := newMockServer(`{"resource": {"ref": {"@ref": "indexes/spells_by_name"}}}`)
+	defer server.Close()
+
+	client := server.client()
+
+	_, err := client.ScopedQuery("tenant",
+		CreateIndex(Obj{"name": "spells_by_name", "source": Collection("spells")}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("secret:tenant:admin"), server.requestHeader(0).Get("Authorization"))
+	require.Equal(t,
+		[]string{`{"create_index":{"object":{"name":"spells_by_name","source":{"collection":"spells"}}}}`},
+		server.requestBodies(),
+	)
+
+	_, err = client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("secret"), server.requestHeader(1).Get("Authorization"))
+}
+
+func TestScopedQueryFromSessionClient(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	session := server.client(Auth(BearerAuth)).NewSessionClient("session-secret")
+
+	_, err := session.ScopedQuery("tenants/acme", NullV{})
+	require.NoError(t, err)
+	require.Equal(t, "Bearer session-secret:tenants/acme:admin", server.requestHeader(0).Get("Authorization"))
+}
+
+func TestReportEmptyResponses(t *testing.T) {
+	server := newMockServer("")
+	defer server.Close()
+
+	_, err := server.client().Query(NullV{})
+	require.Equal(t, EmptyResponseError{Status: 200}, err)
+	require.EqualError(t, err, "Error while parsing response: Empty body with HTTP status 200")
+}
+
+func TestReportNoContentResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := NewFaunaClient("secret", Endpoint(server.URL)).Query(NullV{})
+	require.Equal(t, EmptyResponseError{Status: 204}, err)
+}
+
+func TestNotSendQueriesWithNonFiniteDoubles(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	_, err := server.client().Query(Multiply(math.Inf(1), 2))
+	require.Contains(t, err.Error(), "Error while encoding number to json: Double value +Inf is not finite")
+	require.Empty(t, server.requestBodies())
+}
+
+func TestReportNonJSONResponses(t *testing.T) {
+	server := newMockServer("<html>Under maintenance</html>")
+	defer server.Close()
+
+	_, err := server.client().Query(NullV{})
+	require.IsType(t, InvalidResponseError{}, err)
+	require.Equal(t, 200, err.(InvalidResponseError).Status)
+	require.Contains(t, err.Error(), "Error while parsing response: Invalid JSON body with HTTP status 200.")
+}
+
+func TestReportEmptyResponsesWithLimit(t *testing.T) {
+	server := newMockServer(" ")
+	defer server.Close()
+
+	_, err := server.client(MaxResponseBytes(512)).Query(NullV{})
+	require.Equal(t, EmptyResponseError{Status: 200}, err)
+}
+
+func TestUseDefaultTimeout(t *testing.T) {
+	require.Equal(t, 60*time.Second, NewFaunaClient("secret").http.Timeout)
+}
+
+func TestSetTimeoutOnDefaultClient(t *testing.T) {
+	client := NewFaunaClient("secret", Timeout(5*time.Second))
+	require.Equal(t, 5*time.Second, client.http.Timeout)
+}
+
+func TestKeepTimeoutOfProvidedClient(t *testing.T) {
+	provided := &http.Client{Timeout: 10 * time.Second}
+
+	client := NewFaunaClient("secret", HTTP(provided))
+	require.Equal(t, provided, client.http)
+}
+
+func TestSetTimeoutOnCopyOfProvidedClient(t *testing.T) {
+	transport := &http.Transport{}
+	provided := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	for _, configs := range [][]ClientConfig{
+		{HTTP(provided), Timeout(5 * time.Second)},
+		{Timeout(5 * time.Second), HTTP(provided)},
+	} {
+		client := NewFaunaClient("secret", configs...)
+
+		require.Equal(t, 5*time.Second, client.http.Timeout)
+		require.Equal(t, transport, client.http.Transport)
+		require.Equal(t, 10*time.Second, provided.Timeout)
+	}
+}
+
+func TestDisableTimeout(t *testing.T) {
+	client := NewFaunaClient("secret", Timeout(0))
+	require.Equal(t, time.Duration(0), client.http.Timeout)
+}
+
+func TestTimeoutRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	_, err := NewFaunaClient("secret", Endpoint(server.URL), Timeout(10*time.Millisecond)).Query(NullV{})
+	require.Error(t, err)
+}
+
+func TestUpsert(t *testing.T) {
+	server := newMockServer(`{"resource": {"ref": {"@ref": "classes/spells/42"}, "data": {"name": "Fire"}}}`)
+	defer server.Close()
+
+	value, err := server.client().Upsert(Ref("classes/spells/42"), Obj{"data": Obj{"name": "Fire"}})
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"ref": RefV{ID: "classes/spells/42"}, "data": ObjectV{"name": StringV("Fire")}}, value)
+	require.Equal(t,
+		[]string{toJSON(t, If(
+			Exists(Ref("classes/spells/42")),
+			Update(Ref("classes/spells/42"), Obj{"data": Obj{"name": "Fire"}}),
+			Create(Ref("classes/spells/42"), Obj{"data": Obj{"name": "Fire"}}),
+		))},
+		server.requestBodies(),
+	)
+}
+
+func TestCreateAndGet(t *testing.T) {
+	type spell struct {
+		Name    string   `fauna:"name"`
+		Element []string `fauna:"element"`
+		Cost    int      `fauna:"cost"`
+	}
+
+	server := newMockServer(`{"resource": {
+		"ref": {"@ref": "classes/spells/42"},
+		"class": {"@ref": "classes/spells"},
+		"ts": 1509244539203043,
+		"data": {"name": "Fireball", "element": ["fire"], "cost": 10}
+	}}`)
+	defer server.Close()
+
+	var created spell
+
+	err := server.client().CreateAndGet(Class("spells"), spell{"Fireball", []string{"fire"}, 10}, &created)
+	require.NoError(t, err)
+	require.Equal(t, spell{"Fireball", []string{"fire"}, 10}, created)
+	require.Equal(t,
+		[]string{`{"create":{"class":"spells"},"params":{"object":{"data":{"object":{"cost":10,"element":["fire"],"name":"Fireball"}}}}}`},
+		server.requestBodies(),
+	)
+}
+
+func TestCreateAndGetReportsMissingData(t *testing.T) {
+	server := newMockServer(`{"resource": {"ref": {"@ref": "classes/spells/42"}}}`)
+	defer server.Close()
+
+	var created map[string]string
+
+	err := server.client().CreateAndGet(Class("spells"), map[string]string{}, &created)
+	require.EqualError(t, err, "Error while extracting path: data. Object key data not found")
+}
+
+func TestUnwrapData(t *testing.T) {
+	server := newMockServer(`{"resource": {
+		"ref": {"@ref": "classes/spells/42"},
+		"ts": 1509244539203043,
+		"data": {"name": "Fireball"}
+	}}`)
+	defer server.Close()
+
+	client := server.client()
+
+	value, err := client.Query(Get(Ref("classes/spells/42")), UnwrapData())
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"name": StringV("Fireball")}, value)
+
+	value, err = client.Query(Get(Ref("classes/spells/42")))
+	require.NoError(t, err)
+	require.Contains(t, value, "ref")
+}
+
+func TestUnwrapDataKeepsResultsWithoutData(t *testing.T) {
+	server := newMockServer(
+		`{"resource": {"ref": {"@ref": "databases/prydain"}, "name": "prydain"}}`,
+		`{"resource": [1, 2]}`,
+	)
+	defer server.Close()
+
+	client := server.client()
+
+	value, err := client.Query(Get(Database("prydain")), UnwrapData())
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"ref": RefV{ID: "databases/prydain"}, "name": StringV("prydain")}, value)
+
+	value, err = client.Query(Arr{1, 2}, UnwrapData())
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{LongV(1), LongV(2)}, value)
+}
+
+func TestGetAll(t *testing.T) {
+	server := newMockServer(`{"resource": [
+		{"ref": {"@ref": "classes/spells/1"}, "data": {"name": "Fireball"}},
+		null,
+		{"ref": {"@ref": "classes/spells/3"}, "data": {"name": "Faerie Fire"}}
+	]}`)
+	defer server.Close()
+
+	values, err := server.client().GetAll([]RefV{
+		{ID: "classes/spells/1"},
+		{ID: "classes/spells/2"},
+		{ID: "classes/spells/3"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t,
+		[]Value{
+			ObjectV{"ref": RefV{ID: "classes/spells/1"}, "data": ObjectV{"name": StringV("Fireball")}},
+			NullV{},
+			ObjectV{"ref": RefV{ID: "classes/spells/3"}, "data": ObjectV{"name": StringV("Faerie Fire")}},
+		},
+		values,
+	)
+	require.Equal(t,
+		[]string{`{"collection":[{"@ref":"classes/spells/1"},{"@ref":"classes/spells/2"},{"@ref":"classes/spells/3"}],` +
+			`"map":{"expr":{"else":null,"if":{"exists":{"var":"ref"}},"then":{"get":{"var":"ref"}}},"lambda":"ref"}}`},
+		server.requestBodies(),
+	)
+}
+
+func TestUpdateReturning(t *testing.T) {
+	type spell struct {
+		Name string `fauna:"name"`
+		Cost int    `fauna:"cost"`
+	}
+
+	server := newMockServer(`{"resource": {
+		"ref": {"@ref": "classes/spells/42"},
+		"class": {"@ref": "classes/spells"},
+		"ts": 1509244539203043,
+		"data": {"name": "Fireball", "cost": 5}
+	}}`)
+	defer server.Close()
+
+	var updated spell
+
+	err := server.client().UpdateReturning(Ref("classes/spells/42"), Obj{"cost": 5}, &updated)
+	require.NoError(t, err)
+	require.Equal(t, spell{"Fireball", 5}, updated)
+	require.Equal(t,
+		[]string{`{"params":{"object":{"data":{"object":{"cost":5}}}},"update":{"@ref":"classes/spells/42"}}`},
+		server.requestBodies(),
+	)
+}
+
+func TestCollectionExists(t *testing.T) {
+	server := newMockServer(`{"resource": true}`, `{"resource": false}`)
+	defer server.Close()
+
+	client := server.client()
+
+	exists, err := client.CollectionExists("spells")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = client.CollectionExists("potions")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.Equal(t, []string{
+		`{"exists":{"collection":"spells"}}`,
+		`{"exists":{"collection":"potions"}}`,
+	}, server.requestBodies())
+}
+
+func TestIndexExists(t *testing.T) {
+	server := newMockServer(`{"resource": false}`, `{"resource": true}`)
+	defer server.Close()
+
+	client := server.client()
+
+	exists, err := client.IndexExists("spells_by_element")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	exists, err = client.IndexExists("all_spells")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	require.Equal(t, []string{
+		`{"exists":{"index":"spells_by_element"}}`,
+		`{"exists":{"index":"all_spells"}}`,
+	}, server.requestBodies())
+}
+
+func TestEnsureCollection(t *testing.T) {
+	server := newMockServer(`{"resource": true}`, `{"resource": false}`)
+	defer server.Close()
+
+	client := server.client()
+
+	created, err := client.EnsureCollection("spells", Obj{"history_days": 30})
+	require.NoError(t, err)
+	require.True(t, created)
+
+	created, err = client.EnsureCollection("spells", nil)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	require.Equal(t, []string{
+		`{"else":{"do":[{"create_collection":{"object":{"history_days":30,"name":"spells"}}},true]},` +
+			`"if":{"exists":{"collection":"spells"}},"then":false}`,
+		`{"else":{"do":[{"create_collection":{"object":{"name":"spells"}}},true]},` +
+			`"if":{"exists":{"collection":"spells"}},"then":false}`,
+	}, server.requestBodies())
+}
+
+func TestEnsureIndex(t *testing.T) {
+	server := newMockServer(`{"resource": true}`)
+	defer server.Close()
+
+	params := Obj{"name": "ignored", "source": Collection("spells"), "terms": Arr{Obj{"field": Arr{"data", "element"}}}}
+
+	created, err := server.client().EnsureIndex("spells_by_element", params)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, "ignored", params["name"])
+
+	require.Equal(t, []string{
+		`{"else":{"do":[{"create_index":{"object":{"name":"spells_by_element","source":{"collection":"spells"},` +
+			`"terms":[{"object":{"field":["data","element"]}}]}}},true]},` +
+			`"if":{"exists":{"index":"spells_by_element"}},"then":false}`,
+	}, server.requestBodies())
+}
+
+func TestEnsureFunction(t *testing.T) {
+	server := newMockServer(`{"resource": false}`)
+	defer server.Close()
+
+	created, err := server.client().EnsureFunction("double", Obj{"body": Query(Lambda("x", Multiply(Var("x"), 2)))})
+	require.NoError(t, err)
+	require.False(t, created)
+
+	require.Equal(t, []string{
+		`{"else":{"do":[{"create_function":{"object":{"body":{"query":{"expr":{"multiply":[{"var":"x"},2]},` +
+			`"lambda":"x"}},"name":"double"}}},true]},"if":{"exists":{"function":"double"}},"then":false}`,
+	}, server.requestBodies())
+}
+
+func TestCreateKeySurfacesSecret(t *testing.T) {
+	server := newMockServer(`{"resource": {
+		"ref": {"@ref": {"id": "181388642581742080", "collection": {"@ref": {"id": "keys"}}}},
+		"ts": 1509244539203043,
+		"database": {"@ref": {"id": "tenant", "collection": {"@ref": {"id": "databases"}}}},
+		"role": "server",
+		"secret": "fnACj0eRyRACAMNzLNHt1zQ4dCDEqqIag3GyZsIF",
+		"hashed_secret": "$2a$05$AH6wEpjfjClgJ/5ExuWGZO"
+	}}`)
+	defer server.Close()
+
+	key, err := server.client().CreateKey(Obj{"database": Database("tenant"), "role": "server"})
+	require.NoError(t, err)
+
+	keys := &RefV{ID: "keys"}
+	databases := &RefV{ID: "databases"}
+
+	require.Equal(t, KeyResult{
+		Ref:      RefV{ID: "181388642581742080", Collection: keys},
+		Secret:   "fnACj0eRyRACAMNzLNHt1zQ4dCDEqqIag3GyZsIF",
+		Role:     StringV("server"),
+		Database: RefV{ID: "tenant", Collection: databases},
+	}, key)
+
+	require.Equal(t, []string{
+		`{"create_key":{"object":{"database":{"database":"tenant"},"role":"server"}}}`,
+	}, server.requestBodies())
+}
+
+func TestCreateKeyWithUserDefinedRole(t *testing.T) {
+	server := newMockServer(`{"resource": {
+		"ref": {"@ref": {"id": "181388642581742081", "collection": {"@ref": {"id": "keys"}}}},
+		"role": {"@ref": {"id": "librarian", "collection": {"@ref": {"id": "roles"}}}},
+		"secret": "fnACj0eRyRACAMNzLNHt1zQ4dCDEqqIag3GyZsIG"
+	}}`)
+	defer server.Close()
+
+	key, err := server.client().CreateKey(Obj{"role": Role("librarian")})
+	require.NoError(t, err)
+	require.Equal(t, "fnACj0eRyRACAMNzLNHt1zQ4dCDEqqIag3GyZsIG", key.Secret)
+	require.Equal(t, RefV{ID: "librarian", Collection: &RefV{ID: "roles"}}, key.Role)
+	require.Equal(t, RefV{}, key.Database)
+}
+
+func TestEnsureReportsUnexpectedResult(t *testing.T) {
+	server := newMockServer(`{"resource": {"ref": {"@ref": "classes/spells"}}}`)
+	defer server.Close()
+
+	_, err := server.client().EnsureCollection("spells", nil)
+	require.Error(t, err)
+}
+
+func TestExistsReportsUnexpectedResult(t *testing.T) {
+	server := newMockServer(`{"resource": "yes"}`)
+	defer server.Close()
+
+	_, err := server.client().IndexExists("all_spells")
+	require.Error(t, err)
+}
+
+func TestSetSecret(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	client := server.client()
+	session := client.NewSessionClient("session-secret")
+	client.SetSecret("new-secret")
+
+	_, err := client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("new-secret"), server.requestHeader(0).Get("Authorization"))
+
+	_, err = session.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("session-secret"), server.requestHeader(1).Get("Authorization"))
+}
+
+func TestQueryConcurrentlyWhileRotatingSecrets(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	client := server.client(DefaultTags(map[string]string{"test": "race"}))
+	headers := map[string]bool{BasicAuth("secret"): true, BasicAuth("secret:child:admin"): true}
+
+	for i := 0; i < 10; i++ {
+		headers[BasicAuth(fmt.Sprintf("secret-%d", i))] = true
+		headers[BasicAuth(fmt.Sprintf("secret-%d:child:admin", i))] = true
+	}
+
+	var wait sync.WaitGroup
+	errs := make(chan error, 100)
+
+	for i := 0; i < 10; i++ {
+		wait.Add(2)
+
+		go func(i int) {
+			defer wait.Done()
+			client.SetSecret(fmt.Sprintf("secret-%d", i))
+		}(i)
+
+		go func() {
+			defer wait.Done()
+
+			for j := 0; j < 10; j++ {
+				if _, err := client.ScopedQuery("child", NullV{}, Consistency(ConsistencyEventual)); err != nil {
+					errs <- err
+				}
+
+				if _, err := client.Query(NullV{}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	wait.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 200; i++ {
+		header := server.requestHeader(i).Get("Authorization")
+		require.True(t, headers[header], header)
+	}
+}
+
+func TestUseProxyFromEnvironmentByDefault(t *testing.T) {
+	transport, ok := NewFaunaClient("secret").http.Transport.(*http.Transport)
+
+	require.True(t, ok)
+	require.Equal(t, reflect.ValueOf(http.ProxyFromEnvironment).Pointer(), reflect.ValueOf(transport.Proxy).Pointer())
+}
+
+func TestUseConfiguredProxy(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.local:3128")
+
+	client := NewFaunaClient("secret", Proxy(http.ProxyURL(proxyURL)))
+	transport := client.http.Transport.(*http.Transport)
+
+	request, err := client.prepareRequest(NullV{}, newQueryConfig(nil))
+	require.NoError(t, err)
+
+	proxy, err := transport.Proxy(request)
+	require.NoError(t, err)
+	require.Equal(t, proxyURL, proxy)
+}
+
+func TestSendRequestsThroughProxy(t *testing.T) {
+	proxy := newMockServer(`{"resource": "proxied"}`)
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := NewFaunaClient("secret", Endpoint("http://db.fauna.invalid"), Proxy(http.ProxyURL(proxyURL)))
+
+	value, err := client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, StringV("proxied"), value)
+	require.Equal(t, "db.fauna.invalid", proxy.requests[0].Host)
+}
+
+func TestQueryExplainReturnsBodyWithoutSendingIt(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	body, err := server.client().QueryExplain(Get(Ref("classes/spells/42")))
+	require.NoError(t, err)
+	require.Equal(t, `{"get":{"@ref":"classes/spells/42"}}`, string(body))
+	require.Empty(t, server.requestBodies())
+}
+
+func TestQueryExplainValidatesConfigs(t *testing.T) {
+	_, err := NewFaunaClient("secret").QueryExplain(NullV{}, Tags(map[string]string{"invalid key": "value"}))
+	require.EqualError(t, err, `Invalid query tag key "invalid key": Expected up to 40 letters, digits or underscores`)
+}
+
+func TestQueryWithDryRun(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	value, err := server.client().Query(Arr{1, "two"}, DryRun())
+	require.NoError(t, err)
+	require.Equal(t, BytesV(`[1,"two"]`), value)
+	require.Empty(t, server.requestBodies())
+}
+
+func TestRequestDecoratorModifiesRequests(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	client := server.client(RequestDecorator(func(request *http.Request) error {
+		request.Header.Set("X-Signature", request.Header.Get(requestIDHeader)+"-signed")
+		return nil
+	}), RequestIDFunc(func() string { return "request-1" }))
+
+	_, err := client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, "request-1-signed", server.requestHeader(0).Get("X-Signature"))
+}
+
+func TestRequestDecoratorErrorAbortsQuery(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	decoratorErr := fmt.Errorf("no certificate available")
+	client := server.client(RequestDecorator(func(*http.Request) error { return decoratorErr }))
+
+	_, err := client.Query(NullV{})
+	require.Equal(t, decoratorErr, err)
+	require.Empty(t, server.requestBodies())
+}
+
+func TestDefaultTransportTimeouts(t *testing.T) {
+	transport := NewFaunaClient("secret").http.Transport.(*http.Transport)
+
+	require.Equal(t, 10*time.Second, transport.TLSHandshakeTimeout)
+	require.Equal(t, time.Duration(0), transport.ResponseHeaderTimeout)
+}
+
+func TestConfigureTransportTimeouts(t *testing.T) {
+	client := NewFaunaClient("secret",
+		DialTimeout(time.Second),
+		TLSHandshakeTimeout(2*time.Second),
+		ResponseHeaderTimeout(3*time.Second),
+	)
+
+	transport := client.http.Transport.(*http.Transport)
+
+	require.Equal(t, time.Second, client.dialTimeout)
+	require.Equal(t, 2*time.Second, transport.TLSHandshakeTimeout)
+	require.Equal(t, 3*time.Second, transport.ResponseHeaderTimeout)
+	require.Equal(t, requestTimeout, client.http.Timeout)
+}
+
+func TestUseDefaultTLSConfig(t *testing.T) {
+	transport := NewFaunaClient("secret").http.Transport.(*http.Transport)
+
+	if transport.TLSClientConfig != nil {
+		require.Empty(t, transport.TLSClientConfig.Certificates)
+		require.Empty(t, transport.TLSClientConfig.ServerName)
+	}
+}
+
+func TestUseConfiguredTLSConfig(t *testing.T) {
+	config := &tls.Config{Certificates: []tls.Certificate{{}}, ServerName: "proxy.local"}
+
+	transport := NewFaunaClient("secret", TLSConfig(config)).http.Transport.(*http.Transport)
+
+	require.True(t, config == transport.TLSClientConfig)
+}
+
+func TestIgnoreTLSConfigForProvidedClient(t *testing.T) {
+	provided := &http.Client{}
+
+	client := NewFaunaClient("secret", HTTP(provided), TLSConfig(&tls.Config{}))
+
+	require.Nil(t, client.http.Transport)
+}
+
+func TestResponseHeaderTimeoutFailsSlowResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"resource": null}`))
+	}))
+	defer server.Close()
+
+	client := NewFaunaClient("secret", Endpoint(server.URL), ResponseHeaderTimeout(20*time.Millisecond))
+
+	_, err := client.Query(NullV{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timeout awaiting response headers")
+}
@@ -479,6 +479,13 @@ func (s *ClientTestSuite) TestEvalIfExpression() {
 	s.Require().Equal("true", str)
 }
 
+func (s *ClientTestSuite) TestRoundTripHTMLCharacters() {
+	var str string
+
+	s.queryAndDecode(f.Concat(f.Arr{"<b>", "Fire & Ice", "</b>"}), &str)
+	s.Require().Equal("<b>Fire & Ice</b>", str)
+}
+
 func (s *ClientTestSuite) TestEvalDoExpression() {
 	var ref f.RefV
 
@@ -525,6 +532,21 @@ func (s *ClientTestSuite) TestExecuteForeachExpression() {
 	s.Require().Equal([]string{"Fireball Level 1", "Fireball Level 2"}, arr)
 }
 
+func (s *ClientTestSuite) TestReduceACollection() {
+	var total int
+
+	s.queryAndDecode(
+		f.Reduce(
+			f.Lambda(f.Arr{"total", "spell"}, f.Add(f.Var("total"), f.Select(f.Arr{"data", "cost"}, f.Var("spell")))),
+			0,
+			f.Arr{f.Obj{"data": f.Obj{"cost": 10}}, f.Obj{"data": f.Obj{"cost": 5}}},
+		),
+		&total,
+	)
+
+	s.Require().Equal(15, total)
+}
+
 func (s *ClientTestSuite) TestFilterACollection() {
 	var arr []int
 
@@ -0,0 +1,43 @@
+package faunadb
+
+import "fmt"
+
+/*
+CompositeKey creates an array expression with the terms informed, in order, to match indexes with multiple terms or to
+compare against their values. Terms are encoded as any other expression. For example, for an index with latitude and
+longitude terms:
+
+	client.Query(Paginate(MatchTerm(Index("places_by_location"), CompositeKey([]interface{}{lat, long}))))
+
+Use DecodeCompositeKey to decode composite keys returned by queries, such as index values, back into Go values.
+*/
+func CompositeKey(terms []interface{}) Expr { return wrap(terms) }
+
+/*
+DecodeCompositeKey decodes the elements of a composite key, an array with one element per term, into the targets
+informed, in order. The key must have exactly one element per target. For example:
+
+	var lat, long float64
+
+	if err := DecodeCompositeKey(value, &lat, &long); err != nil {
+		panic(err)
+	}
+*/
+func DecodeCompositeKey(key Value, targets ...interface{}) error {
+	arr, err := ToArrayV(key)
+	if err != nil {
+		return err
+	}
+
+	if len(arr) != len(targets) {
+		return fmt.Errorf("Error while decoding composite key: Expected %d elements but got %d", len(targets), len(arr))
+	}
+
+	for i, target := range targets {
+		if err := arr[i].Get(target); err != nil {
+			return DecodeError{path: pathFromIndexes(i), err: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package faunadb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializeCompositeKey(t *testing.T) {
+	assertJSON(t,
+		MatchTerm(Index("places_by_location"), CompositeKey([]interface{}{51.5, -0.12})),
+		`{"match":{"index":"places_by_location"},"terms":[51.5,-0.12]}`,
+	)
+
+	assertJSON(t,
+		CompositeKey([]interface{}{"uk", Var("city"), Obj{"zone": 1}}),
+		`["uk",{"var":"city"},{"object":{"zone":1}}]`,
+	)
+}
+
+func TestDecodeTwoElementsCompositeKey(t *testing.T) {
+	var lat, long float64
+
+	require.NoError(t, DecodeCompositeKey(ArrayV{DoubleV(51.5), DoubleV(-0.12)}, &lat, &long))
+	require.Equal(t, 51.5, lat)
+	require.Equal(t, -0.12, long)
+}
+
+func TestDecodeThreeElementsCompositeKey(t *testing.T) {
+	type location struct {
+		Lat  float64 `fauna:"lat"`
+		Long float64 `fauna:"long"`
+	}
+
+	var country string
+	var loc location
+	var ref RefV
+
+	key := ArrayV{
+		StringV("uk"),
+		ObjectV{"lat": DoubleV(51.5), "long": DoubleV(-0.12)},
+		RefV{ID: "classes/places/1"},
+	}
+
+	require.NoError(t, DecodeCompositeKey(key, &country, &loc, &ref))
+	require.Equal(t, "uk", country)
+	require.Equal(t, location{51.5, -0.12}, loc)
+	require.Equal(t, RefV{ID: "classes/places/1"}, ref)
+}
+
+func TestCompositeKeyRoundTrip(t *testing.T) {
+	var lat, long float64
+	var name string
+
+	value, err := ParseValue(strings.NewReader(toJSON(t, CompositeKey([]interface{}{51.5, -0.12, "London"}))))
+	require.NoError(t, err)
+	require.NoError(t, DecodeCompositeKey(value, &lat, &long, &name))
+	require.Equal(t, []interface{}{51.5, -0.12, "London"}, []interface{}{lat, long, name})
+}
+
+func TestFailToDecodeCompositeKeyWithWrongLength(t *testing.T) {
+	var lat, long float64
+
+	require.EqualError(t,
+		DecodeCompositeKey(ArrayV{DoubleV(51.5)}, &lat, &long),
+		"Error while decoding composite key: Expected 2 elements but got 1",
+	)
+}
+
+func TestFailToDecodeCompositeKeyElements(t *testing.T) {
+	var lat, long float64
+
+	require.EqualError(t,
+		DecodeCompositeKey(ArrayV{DoubleV(51.5), StringV("west")}, &lat, &long),
+		"Error while decoding fauna value at: 1. Can not assign value of type \"faunadb.StringV\" to a value of type \"float64\"",
+	)
+}
+
+func TestFailToDecodeNonArrayCompositeKey(t *testing.T) {
+	var lat float64
+
+	require.EqualError(t,
+		DecodeCompositeKey(DoubleV(51.5), &lat),
+		"Error while converting value: Expected value to be an array but was a faunadb.DoubleV",
+	)
+}
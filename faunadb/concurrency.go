@@ -0,0 +1,54 @@
+package faunadb
+
+import "time"
+
+/*
+MaxConcurrency configures the FaunaClient structure to send at most the number of requests informed at the same time.
+Queries sent while the limit is reached block until one of the requests in flight finishes, providing backpressure
+to callers instead of opening more connections to FaunaDB. Result streams hold their request until they are closed.
+
+The limit is shared by the client and its session clients. Retries wait for the backoff without holding a request.
+Queries blocked for longer than the client's Timeout fail with a ConcurrencyLimitError, so a stuck request does not
+block every other caller. A limit of zero or less means no limit.
+*/
+func MaxConcurrency(limit int) ClientConfig {
+	return func(cli *FaunaClient) { cli.maxConcurrency = limit }
+}
+
+// acquire blocks until the client can send one more request without exceeding its MaxConcurrency, or fails with a
+// ConcurrencyLimitError once the client's Timeout is spent waiting.
+func (client *FaunaClient) acquire() error {
+	if client.slots == nil {
+		return nil
+	}
+
+	select {
+	case client.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	timeout := client.http.Timeout
+
+	if timeout <= 0 {
+		client.slots <- struct{}{}
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case client.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ConcurrencyLimitError{Limit: cap(client.slots), Timeout: timeout}
+	}
+}
+
+// release frees the request acquired by a previous call to acquire.
+func (client *FaunaClient) release() {
+	if client.slots != nil {
+		<-client.slots
+	}
+}
@@ -0,0 +1,152 @@
+package faunadb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// inFlightServer responds to requests after a delay, recording the maximum number of requests served at once.
+type inFlightServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	inFlight int
+	max      int
+}
+
+func newInFlightServer(delay time.Duration, response string) *inFlightServer {
+	server := &inFlightServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mutex.Lock()
+		server.inFlight++
+		if server.inFlight > server.max {
+			server.max = server.inFlight
+		}
+		server.mutex.Unlock()
+
+		time.Sleep(delay)
+
+		server.mutex.Lock()
+		server.inFlight--
+		server.mutex.Unlock()
+
+		_, _ = w.Write([]byte(response))
+	}))
+
+	return server
+}
+
+func (server *inFlightServer) maxInFlight() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.max
+}
+
+func queryConcurrently(t *testing.T, clients []*FaunaClient, queries int) {
+	errs := make(chan error, queries)
+
+	for i := 0; i < queries; i++ {
+		go func(client *FaunaClient) {
+			_, err := client.Query(NullV{})
+			errs <- err
+		}(clients[i%len(clients)])
+	}
+
+	for i := 0; i < queries; i++ {
+		require.NoError(t, <-errs)
+	}
+}
+
+func TestLimitConcurrentRequests(t *testing.T) {
+	server := newInFlightServer(20*time.Millisecond, `{"resource": null}`)
+	defer server.Close()
+
+	client := NewFaunaClient("secret", Endpoint(server.URL), MaxConcurrency(2))
+
+	queryConcurrently(t, []*FaunaClient{client}, 10)
+
+	require.Equal(t, 2, server.maxInFlight())
+}
+
+func TestShareConcurrencyLimitWithSessionClients(t *testing.T) {
+	server := newInFlightServer(20*time.Millisecond, `{"resource": null}`)
+	defer server.Close()
+
+	client := NewFaunaClient("secret", Endpoint(server.URL), MaxConcurrency(2))
+
+	queryConcurrently(t, []*FaunaClient{client, client.NewSessionClient("session")}, 10)
+
+	require.Equal(t, 2, server.maxInFlight())
+}
+
+func TestDoNotLimitConcurrentRequestsByDefault(t *testing.T) {
+	server := newInFlightServer(50*time.Millisecond, `{"resource": null}`)
+	defer server.Close()
+
+	queryConcurrently(t, []*FaunaClient{NewFaunaClient("secret", Endpoint(server.URL))}, 4)
+
+	require.True(t, server.maxInFlight() > 1)
+}
+
+func TestResultStreamsHoldRequestsUntilClosed(t *testing.T) {
+	server := newMockServer(`{"resource": [1, 2]}`)
+	defer server.Close()
+
+	client := server.client(MaxConcurrency(1))
+
+	stream, err := client.QueryStream(NullV{})
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := client.Query(NullV{})
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Query sent while the stream held the only request")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, stream.Close())
+	require.NoError(t, <-done)
+}
+
+func TestReleaseRequestsOfFailedStreams(t *testing.T) {
+	server := newMockServer(`{"resource": "not an array"}`)
+	defer server.Close()
+
+	client := server.client(MaxConcurrency(1))
+
+	_, err := client.QueryStream(NullV{})
+	require.Error(t, err)
+
+	_, err = client.Query(NullV{})
+	require.NoError(t, err)
+}
+
+func TestFailToAcquireRequestAfterTimeout(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	client := server.client(MaxConcurrency(1), Timeout(50*time.Millisecond))
+	client.slots <- struct{}{} // A stuck request holding the only slot
+	defer client.release()
+
+	start := time.Now()
+	_, err := client.Query(NullV{})
+
+	require.Equal(t, ConcurrencyLimitError{Limit: 1, Timeout: 50 * time.Millisecond}, err)
+	require.EqualError(t, err, "Error while sending request: Timed out after 50ms waiting for one of 1 requests in flight")
+	require.True(t, time.Since(start) < time.Second)
+
+	_, err = client.QueryStream(NullV{})
+	require.IsType(t, ConcurrencyLimitError{}, err)
+	require.Empty(t, server.requestBodies())
+}
@@ -0,0 +1,110 @@
+package faunadb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+)
+
+/*
+Cursor wraps the opaque "after" or "before" cursor of a page returned by the Paginate function. Cursors can be
+marshaled to URL safe strings and parsed back, allowing stateless pagination across requests. For example:
+
+	// Build a link to the next page
+	text, _ := page.AfterCursor().MarshalText()
+
+	// Fetch the next page from the link received
+	var cursor Cursor
+	if err := cursor.UnmarshalText(text); err != nil {
+		panic(err)
+	}
+
+	client.Query(Paginate(Documents(Collection("spells")), After(cursor)))
+
+The zero value represents the absence of a cursor, marshaled as an empty string.
+*/
+type Cursor struct {
+	Value Value
+}
+
+func (cursor Cursor) expr() {}
+
+// IsZero returns true if the cursor has no value.
+func (cursor Cursor) IsZero() bool { return cursor.Value == nil }
+
+// MarshalJSON implements json.Marshaler by encoding the cursor as its value.
+func (cursor Cursor) MarshalJSON() ([]byte, error) {
+	if cursor.Value == nil {
+		return marshalJSON(NullV{})
+	}
+
+	return marshalJSON(cursor.Value)
+}
+
+// MarshalText implements encoding.TextMarshaler by encoding the cursor value as URL safe base64 of its JSON, in
+// the representation of query responses read back by ParseValue.
+func (cursor Cursor) MarshalText() ([]byte, error) {
+	if cursor.Value == nil {
+		return []byte{}, nil
+	}
+
+	raw, err := marshalJSON(responseValue(cursor.Value))
+	if err != nil {
+		return nil, err
+	}
+
+	text := make([]byte, base64.RawURLEncoding.EncodedLen(len(raw)))
+	base64.RawURLEncoding.Encode(text, raw)
+
+	return text, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by parsing a cursor previously encoded with MarshalText.
+func (cursor *Cursor) UnmarshalText(text []byte) (err error) {
+	if len(text) == 0 {
+		cursor.Value = nil
+		return
+	}
+
+	raw := make([]byte, base64.RawURLEncoding.DecodedLen(len(text)))
+
+	if _, err = base64.RawURLEncoding.Decode(raw, text); err != nil {
+		return fmt.Errorf("Error while parsing cursor: %s", err)
+	}
+
+	var value Value
+
+	if value, err = ParseValue(bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("Error while parsing cursor: %s", err)
+	}
+
+	cursor.Value = value
+	return
+}
+
+// responseValue converts the value informed to its representation in query responses, which differs from the
+// representation in queries only for objects: they are escaped as @obj, so keys starting with @ are preserved.
+func responseValue(value Value) interface{} {
+	switch v := value.(type) {
+	case ObjectV:
+		obj := make(map[string]interface{}, len(v))
+
+		for key, elem := range v {
+			obj[key] = responseValue(elem)
+		}
+
+		return map[string]interface{}{"@obj": obj}
+	case ArrayV:
+		arr := make([]interface{}, len(v))
+
+		for i, elem := range v {
+			arr[i] = responseValue(elem)
+		}
+
+		return arr
+	case SetRefV:
+		return map[string]interface{}{"@set": responseValue(ObjectV(v.Parameters))}
+	default:
+		return v
+	}
+}
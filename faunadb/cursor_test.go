@@ -0,0 +1,107 @@
+package faunadb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{ArrayV{LongV(10), RefV{ID: "classes/spells/42"}}}
+
+	text, err := cursor.MarshalText()
+	require.NoError(t, err)
+	require.NotContains(t, string(text), "=")
+
+	var parsed Cursor
+	require.NoError(t, parsed.UnmarshalText(text))
+	require.Equal(t, cursor, parsed)
+}
+
+func TestCursorRoundTripWithObjectsAndRefs(t *testing.T) {
+	ref := RefV{ID: "42", Collection: &RefV{ID: "spells", Collection: &RefV{ID: "collections"}}}
+
+	cursors := []Cursor{
+		{ArrayV{ObjectV{"a": LongV(1)}, ref}},
+		{ArrayV{ObjectV{"@ref": StringV("not a ref"), "nested": ObjectV{"@ts": LongV(1)}}, ref}},
+		{ArrayV{SetRefV{map[string]Value{"match": ref, "terms": ObjectV{"@obj": StringV("fire")}}}}},
+		{ObjectV{}},
+	}
+
+	for _, cursor := range cursors {
+		text, err := cursor.MarshalText()
+		require.NoError(t, err)
+
+		var parsed Cursor
+		require.NoError(t, parsed.UnmarshalText(text))
+		require.Equal(t, cursor, parsed)
+	}
+}
+
+func TestZeroCursorMarshalsAsEmptyText(t *testing.T) {
+	text, err := Cursor{}.MarshalText()
+	require.NoError(t, err)
+	require.Empty(t, text)
+
+	parsed := Cursor{LongV(1)}
+	require.NoError(t, parsed.UnmarshalText(text))
+	require.True(t, parsed.IsZero())
+}
+
+func TestFailToParseInvalidCursor(t *testing.T) {
+	var cursor Cursor
+
+	require.EqualError(t, cursor.UnmarshalText([]byte("not base64!")),
+		"Error while parsing cursor: illegal base64 data at input byte 3")
+
+	require.Error(t, cursor.UnmarshalText([]byte("bm90IGpzb24")))
+}
+
+func TestSerializeCursorAsPaginateOption(t *testing.T) {
+	cursor := Cursor{ArrayV{RefV{ID: "classes/spells/42"}}}
+
+	assertJSON(t,
+		Paginate(Documents(Collection("spells")), After(cursor)),
+		`{"after":[{"@ref":"classes/spells/42"}],"paginate":{"documents":{"collection":"spells"}}}`,
+	)
+}
+
+func TestResumePaginatorFromCursor(t *testing.T) {
+	server := newMockServer(
+		`{"resource": {"data": [{"@ref": "classes/spells/1"}], "after": [{"@ref": "classes/spells/2"}]}}`,
+		`{"resource": {"data": [{"@ref": "classes/spells/2"}]}}`,
+	)
+	defer server.Close()
+
+	client := server.client()
+	pages := client.AllDocuments("spells", Size(1))
+	require.True(t, pages.Cursor().IsZero())
+
+	_, err := pages.Next()
+	require.NoError(t, err)
+
+	text, err := pages.Cursor().MarshalText()
+	require.NoError(t, err)
+
+	var cursor Cursor
+	require.NoError(t, cursor.UnmarshalText(text))
+
+	resumed := client.AllDocuments("spells", Size(1), After(cursor))
+
+	page, err := resumed.Next()
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{RefV{ID: "classes/spells/2"}}, page)
+	require.True(t, resumed.Cursor().IsZero())
+
+	require.Equal(t,
+		`{"after":[{"@ref":"classes/spells/2"}],"paginate":{"documents":{"collection":"spells"}},"size":1}`,
+		server.requestBodies()[1],
+	)
+}
+
+func TestPageCursors(t *testing.T) {
+	page := Page{Data: ArrayV{}, Before: ArrayV{LongV(1)}}
+
+	require.Equal(t, Cursor{ArrayV{LongV(1)}}, page.BeforeCursor())
+	require.True(t, page.AfterCursor().IsZero())
+}
@@ -1,10 +1,17 @@
 package faunadb
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"strings"
+	"time"
 )
 
+var rawMessageType = reflect.TypeOf((*json.RawMessage)(nil)).Elem()
+
 // A DecodeError describes an error when decoding a Fauna Value to a native Go lang type
 type DecodeError struct {
 	path path
@@ -42,6 +49,29 @@ func newValueDecoder(source interface{}) *valueDecoder {
 }
 
 func (c *valueDecoder) assign(value interface{}) error {
+	if faunaValue, ok := value.(Value); ok && c.targetType == rawMessageType {
+		return c.assignRawJSON(faunaValue)
+	}
+
+	if c.targetType.Kind() == reflect.String {
+		switch t := value.(type) {
+		case TimeV:
+			value = StringV(time.Time(t).UTC().Format(timeFormat))
+		case DateV:
+			value = StringV(time.Time(t).Format(dateFormat))
+		}
+	}
+
+	if str, ok := value.(StringV); ok {
+		if enum, found := lookupEnum(c.targetType); found {
+			return c.assignEnum(enum, str)
+		}
+
+		if unmarshaler, ok := c.textUnmarshaler(); ok {
+			return c.assignText(unmarshaler, str)
+		}
+	}
+
 	source, sourceType := indirectValue(value)
 
 	if sourceType.AssignableTo(c.targetType) {
@@ -59,6 +89,62 @@ func (c *valueDecoder) assign(value interface{}) error {
 	}
 }
 
+// textUnmarshaler returns the target as an encoding.TextUnmarshaler, such as net.IP, when it implements it.
+func (c *valueDecoder) textUnmarshaler() (encoding.TextUnmarshaler, bool) {
+	if c.target.Kind() == reflect.Interface || !c.target.CanAddr() {
+		return nil, false
+	}
+
+	unmarshaler, ok := c.target.Addr().Interface().(encoding.TextUnmarshaler)
+	return unmarshaler, ok
+}
+
+func (c *valueDecoder) assignText(unmarshaler encoding.TextUnmarshaler, str StringV) error {
+	if err := unmarshaler.UnmarshalText([]byte(str)); err != nil {
+		return DecodeError{err: fmt.Errorf("Can not decode \"%s\" into a value of type \"%s\": %s", str, c.targetType, err)}
+	}
+
+	return nil
+}
+
+// assignRawJSON encodes the value as plain JSON: objects are not escaped as FaunaDB objects,
+// while special types, such as refs and timestamps, keep their @-prefixed representation.
+func (c *valueDecoder) assignRawJSON(value Value) error {
+	raw, err := marshalJSON(plainJSON(value))
+
+	if err != nil {
+		return DecodeError{err: err}
+	}
+
+	c.target.SetBytes(raw)
+	return nil
+}
+
+func plainJSON(value Value) interface{} {
+	switch v := value.(type) {
+	case ObjectV:
+		obj := make(map[string]interface{}, len(v))
+
+		for key, elem := range v {
+			obj[key] = plainJSON(elem)
+		}
+
+		return obj
+	case ArrayV:
+		arr := make([]interface{}, len(v))
+
+		for i, elem := range v {
+			arr[i] = plainJSON(elem)
+		}
+
+		return arr
+	case SetRefV:
+		return map[string]interface{}{"@set": plainJSON(ObjectV(v.Parameters))}
+	default:
+		return v
+	}
+}
+
 func (c *valueDecoder) decodeArray(arr ArrayV) error {
 	if err := c.assign(arr); err == nil {
 		return nil
@@ -99,6 +185,14 @@ func (c *valueDecoder) decodeMap(obj ObjectV) error {
 }
 
 func (c *valueDecoder) makeNewMap(obj map[string]Value) error {
+	keyType := c.targetType.Key()
+
+	if keyType.Kind() != reflect.String {
+		return DecodeError{
+			err: fmt.Errorf("Can not decode map into a value of type \"%s\": Map keys must be strings", c.targetType),
+		}
+	}
+
 	newMap := reflect.MakeMap(c.targetType)
 	elemType := c.targetType.Elem()
 
@@ -109,7 +203,7 @@ func (c *valueDecoder) makeNewMap(obj map[string]Value) error {
 			return DecodeError{path: pathFromKeys(key), err: err}
 		}
 
-		newMap.SetMapIndex(reflect.ValueOf(key), newElem)
+		newMap.SetMapIndex(reflect.ValueOf(key).Convert(keyType), newElem)
 	}
 
 	return c.assign(newMap)
@@ -118,16 +212,77 @@ func (c *valueDecoder) makeNewMap(obj map[string]Value) error {
 func (c *valueDecoder) fillStructFields(obj map[string]Value) error {
 	newStruct := reflect.New(c.targetType).Elem()
 
-	for key, field := range exportedStructFields(newStruct) {
-		value, found := obj[key]
+	var missing []string
+
+	for _, info := range exportedStructFields(c.targetType) {
+		value, found := obj[info.name]
 		if !found {
+			if info.options.has(requiredOption) {
+				missing = append(missing, info.name)
+			}
+
 			continue
 		}
 
-		if err := value.Get(field); err != nil {
-			return DecodeError{path: pathFromKeys(key), err: err}
+		if err := decodeStructField(value, info.field(newStruct)); err != nil {
+			return DecodeError{path: pathFromKeys(info.name), err: err}
 		}
 	}
 
+	if len(missing) > 0 {
+		return DecodeError{err: fmt.Errorf("Missing required fields: %s", strings.Join(missing, ", "))}
+	}
+
 	return c.assign(newStruct)
 }
+
+// durationFromUnits converts a number of the unit informed into a number of nanoseconds, failing when the duration
+// does not fit an int64. Other values are returned unchanged.
+func durationFromUnits(value Value, unit time.Duration) (Value, error) {
+	switch num := value.(type) {
+	case LongV:
+		if int64(num) > math.MaxInt64/int64(unit) || int64(num) < math.MinInt64/int64(unit) {
+			return nil, DecodeError{err: fmt.Errorf("Can not decode %d into a duration: Value out of range", num)}
+		}
+
+		return LongV(int64(num) * int64(unit)), nil
+	case DoubleV:
+		nanos := float64(num) * float64(unit)
+
+		if math.IsNaN(nanos) || nanos >= math.MaxInt64 || nanos < math.MinInt64 {
+			return nil, DecodeError{err: fmt.Errorf("Can not decode %v into a duration: Value out of range", num)}
+		}
+
+		return LongV(nanos), nil
+	}
+
+	return value, nil
+}
+
+func decodeStructField(value Value, field structField) error {
+	if unit, ok := field.options.durationUnit(); ok && field.value.Type() == durationType {
+		var err error
+
+		if value, err = durationFromUnits(value, unit); err != nil {
+			return err
+		}
+	}
+
+	if unit, ok := field.options.epochUnit(); ok && isIntegerKind(field.value.Kind()) {
+		if ts, isTime := value.(TimeV); isTime {
+			value = LongV(epochFromTime(time.Time(ts), unit))
+		}
+	}
+
+	if field.options.has(boolnumOption) && field.value.Kind() == reflect.Bool {
+		if num, isLong := value.(LongV); isLong {
+			if num != 0 && num != 1 {
+				return DecodeError{err: fmt.Errorf("Can not decode %d into a boolnum field: Expected 0 or 1", num)}
+			}
+
+			value = BooleanV(num == 1)
+		}
+	}
+
+	return value.Get(field.value)
+}
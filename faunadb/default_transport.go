@@ -0,0 +1,31 @@
+//go:build go1.13
+// +build go1.13
+
+package faunadb
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultTransport clones http.DefaultTransport, keeping its connection pooling and HTTP/2 settings.
+func defaultTransport() *http.Transport {
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		return transport.Clone()
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+func setDialTimeout(transport *http.Transport, timeout time.Duration) {
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+}
@@ -0,0 +1,24 @@
+//go:build !go1.7
+// +build !go1.7
+
+package faunadb
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultTransport creates a transport with the settings of http.DefaultTransport, which can not be cloned before
+// Go 1.13. ExpectContinueTimeout is left unset since Go 1.5 does not support it.
+func defaultTransport() *http.Transport {
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		Dial:                (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+func setDialTimeout(transport *http.Transport, timeout time.Duration) {
+	transport.Dial = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).Dial
+}
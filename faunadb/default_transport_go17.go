@@ -0,0 +1,27 @@
+//go:build go1.7 && !go1.13
+// +build go1.7,!go1.13
+
+package faunadb
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultTransport creates a transport with the settings of http.DefaultTransport, which can not be cloned before
+// Go 1.13. HTTP/2 is used as long as no TLS configuration is set, as for http.DefaultTransport.
+func defaultTransport() *http.Transport {
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+func setDialTimeout(transport *http.Transport, timeout time.Duration) {
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+}
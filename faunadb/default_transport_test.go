@@ -0,0 +1,45 @@
+//go:build go1.13
+// +build go1.13
+
+package faunadb
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTransportKeepsDefaultSettings(t *testing.T) {
+	defaults := http.DefaultTransport.(*http.Transport)
+	transport := NewFaunaClient("secret").http.Transport.(*http.Transport)
+
+	require.False(t, defaults == transport)
+	require.True(t, transport.ForceAttemptHTTP2)
+	require.Equal(t, defaults.MaxIdleConns, transport.MaxIdleConns)
+	require.Equal(t, defaults.IdleConnTimeout, transport.IdleConnTimeout)
+	require.Equal(t, defaults.ExpectContinueTimeout, transport.ExpectContinueTimeout)
+	require.Equal(t, defaults.TLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+	require.NotNil(t, transport.DialContext)
+	require.Nil(t, transport.Dial)
+}
+
+func TestConfiguringTransportDoesNotModifyDefaultTransport(t *testing.T) {
+	defaults := http.DefaultTransport.(*http.Transport)
+	proxyURL, _ := url.Parse("http://proxy.local:3128")
+
+	NewFaunaClient("secret",
+		Proxy(http.ProxyURL(proxyURL)),
+		TLSConfig(&tls.Config{ServerName: "proxy.local"}),
+		DialTimeout(time.Second),
+		TLSHandshakeTimeout(2*time.Second),
+		ResponseHeaderTimeout(3*time.Second),
+	)
+
+	require.Equal(t, 10*time.Second, defaults.TLSHandshakeTimeout)
+	require.Equal(t, time.Duration(0), defaults.ResponseHeaderTimeout)
+	require.True(t, defaults.TLSClientConfig == nil || defaults.TLSClientConfig.ServerName == "")
+}
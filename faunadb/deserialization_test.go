@@ -2,8 +2,14 @@ package faunadb
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
+	"net"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -47,6 +53,15 @@ func TestNotDeserializeUint(t *testing.T) {
 	)
 }
 
+func TestNotDeserializeOutOfRangeDouble(t *testing.T) {
+	var num float64
+
+	require.EqualError(t,
+		decodeJSON("1.5e400", &num),
+		"Error while parsing number 1.5e400: Double value is not finite",
+	)
+}
+
 func TestDeserializeDoubleV(t *testing.T) {
 	var num DoubleV
 
@@ -97,7 +112,48 @@ func TestDeserializeRefV(t *testing.T) {
 	var ref RefV
 
 	require.NoError(t, decodeJSON(`{ "@ref": "classes/spells/42" }`, &ref))
-	require.Equal(t, RefV{"classes/spells/42"}, ref)
+	require.Equal(t, RefV{ID: "classes/spells/42"}, ref)
+}
+
+func TestDeserializeStructuredRefV(t *testing.T) {
+	var ref RefV
+
+	json := `
+	{
+		"@ref": {
+			"id": "42",
+			"collection": {
+				"@ref": {
+					"id": "spells",
+					"collection": { "@ref": { "id": "collections" } },
+					"database": { "@ref": { "id": "prydain", "collection": { "@ref": { "id": "databases" } } } }
+				}
+			}
+		}
+	}
+	`
+
+	require.NoError(t, decodeJSON(json, &ref))
+	require.Equal(t,
+		RefV{
+			ID: "42",
+			Collection: &RefV{
+				ID:         "spells",
+				Collection: &RefV{ID: "collections"},
+				Database:   &RefV{ID: "prydain", Collection: &RefV{ID: "databases"}},
+			},
+		},
+		ref,
+	)
+}
+
+func TestNotDeserializeRefWithoutID(t *testing.T) {
+	var ref RefV
+
+	require.EqualError(t,
+		decodeJSON(`{ "@ref": { "collection": { "@ref": { "id": "collections" } } } }`, &ref),
+		"Expected a ref id but got <nil>",
+	)
 }
 
 func TestDeserializeDateV(t *testing.T) {
@@ -128,6 +184,66 @@ func TestDeserializeTime(t *testing.T) {
 	require.Equal(t, time.Date(1970, time.January, 1, 0, 0, 0, 5, time.UTC), localTime)
 }
 
+func TestDeserializeTimeWithOffsets(t *testing.T) {
+	expected := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, raw := range []string{
+		"2017-01-01T10:00:00Z",
+		"2017-01-01T10:00:00+00:00",
+		"2017-01-01T07:00:00-03:00",
+		"2017-01-01T12:00:00+0200",
+		"2017-01-01T10:00:00",
+	} {
+		var localTime time.Time
+
+		require.NoError(t, decodeJSON(`{ "@ts": "`+raw+`" }`, &localTime), raw)
+		require.Equal(t, expected, localTime, raw)
+	}
+}
+
+func TestDeserializeTimeWithFractionalSecondsAndOffset(t *testing.T) {
+	var localTime time.Time
+
+	require.NoError(t, decodeJSON(`{ "@ts": "2017-01-01T10:00:00.123456+00:00" }`, &localTime))
+	require.Equal(t, time.Date(2017, time.January, 1, 10, 0, 0, 123456000, time.UTC), localTime)
+}
+
+func TestDeserializeInvalidTime(t *testing.T) {
+	var localTime time.Time
+
+	err := decodeJSON(`{ "@ts": "not a time" }`, &localTime)
+	require.IsType(t, &time.ParseError{}, err)
+	require.Equal(t, time.RFC3339Nano, err.(*time.ParseError).Layout)
+}
+
+func TestDeserializeTimeIntoString(t *testing.T) {
+	var str string
+
+	require.NoError(t, decodeJSON(`{ "@ts": "2017-01-01T10:00:00.123456Z" }`, &str))
+	require.Equal(t, "2017-01-01T10:00:00.123456Z", str)
+
+	assertJSON(t, Time(str), `{"time":"2017-01-01T10:00:00.123456Z"}`)
+
+	parsed, err := time.Parse(time.RFC3339Nano, str)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2017, time.January, 1, 10, 0, 0, 123456000, time.UTC), parsed)
+}
+
+func TestDeserializeTimeWithLocationIntoString(t *testing.T) {
+	var str StringV
+
+	localTime := TimeV(time.Date(2017, time.January, 1, 7, 0, 0, 0, time.FixedZone("BRT", -3*60*60)))
+	require.NoError(t, localTime.Get(&str))
+	require.Equal(t, StringV("2017-01-01T10:00:00Z"), str)
+}
+
+func TestDeserializeDateIntoString(t *testing.T) {
+	var str string
+
+	require.NoError(t, decodeJSON(`{ "@date": "1970-01-03" }`, &str))
+	require.Equal(t, "1970-01-03", str)
+}
+
 func TestDeserializeBytesV(t *testing.T) {
 	var bytes BytesV
 
@@ -233,6 +349,12 @@ func TestDeserializeObjectLiteral(t *testing.T) {
 	require.Equal(t, map[string]string{"@name": "Test"}, object)
 }
 
+func TestDeserializeObjectLiteralWithSpecialKeys(t *testing.T) {
+	value, err := ParseValue(strings.NewReader(`{"@obj": {"@ref": "not a ref", "@ts": 1}}`))
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"@ref": StringV("not a ref"), "@ts": LongV(1)}, value)
+}
+
 func TestDeserializeEmptyObject(t *testing.T) {
 	var object map[string]string
 
@@ -249,6 +371,27 @@ func TestDeserializeObjectOnInvalidTarget(t *testing.T) {
 	)
 }
 
+func TestDeserializeMapWithNamedStringKeys(t *testing.T) {
+	type element string
+
+	var costs map[element]int
+
+	require.NoError(t, decodeJSON(`{ "fire": 10, "water": 5 }`, &costs))
+	require.Equal(t, map[element]int{"fire": 10, "water": 5}, costs)
+
+	var values map[element]Value
+
+	require.NoError(t, decodeJSON(`{ "fire": { "@ref": "classes/spells/42" } }`, &values))
+	require.Equal(t, map[element]Value{"fire": RefV{ID: "classes/spells/42"}}, values)
+}
+
+func TestNotDeserializeMapWithNonStringKeys(t *testing.T) {
+	var obj map[int]string
+
+	require.EqualError(t, decodeJSON(`{ "1": "one" }`, &obj),
+		"Error while decoding fauna value at: <root>. Can not decode map into a value of type \"map[int]string\": Map keys must be strings")
+}
+
 func TestDeserializeStruct(t *testing.T) {
 	var object struct{ Name string }
 
@@ -268,6 +411,356 @@ func TestDeserializeStructWithTags(t *testing.T) {
 	require.Equal(t, object{"Jhon", 10}, obj)
 }
 
+func TestDeserializeStructWithEpochFields(t *testing.T) {
+	type event struct {
+		Seconds int64  `fauna:"seconds,unix"`
+		Millis  int64  `fauna:"millis,unixmilli"`
+		Micros  uint64 `fauna:"micros,unixmicro"`
+		Before  int64  `fauna:"before,unixmilli"`
+		Number  int64  `fauna:"number,unixmilli"`
+	}
+
+	var obj event
+
+	json := `
+	{
+		"seconds": { "@ts": "2017-01-01T10:00:00.999Z" },
+		"millis": { "@ts": "2017-01-01T10:00:00.123456Z" },
+		"micros": { "@ts": "2017-01-01T10:00:00.123456789Z" },
+		"before": { "@ts": "1969-12-31T23:59:59.5Z" },
+		"number": 42
+	}
+	`
+
+	require.NoError(t, decodeJSON(json, &obj))
+	require.Equal(t, event{1483264800, 1483264800123, 1483264800123456, -500, 42}, obj)
+}
+
+func TestDeserializeStructWithDurationFields(t *testing.T) {
+	type session struct {
+		TTL      time.Duration `fauna:"ttl,seconds"`
+		Millis   time.Duration `fauna:"millis,milliseconds"`
+		Fraction time.Duration `fauna:"fraction,seconds"`
+		Nanos    time.Duration `fauna:"nanos,nanoseconds"`
+		Untagged time.Duration `fauna:"untagged"`
+	}
+
+	var obj session
+
+	require.NoError(t, decodeJSON(`{ "ttl": 3600, "millis": 1500, "fraction": 0.25, "nanos": 7, "untagged": 5 }`, &obj))
+	require.Equal(t, session{time.Hour, 1500 * time.Millisecond, 250 * time.Millisecond, 7, 5}, obj)
+}
+
+func TestDeserializeDurationOutsideStructsAsNanoseconds(t *testing.T) {
+	var duration time.Duration
+
+	require.NoError(t, LongV(5).Get(&duration))
+	require.Equal(t, 5*time.Nanosecond, duration)
+}
+
+func TestNotDeserializeOutOfRangeDurationFields(t *testing.T) {
+	type session struct {
+		TTL time.Duration `fauna:"ttl,seconds"`
+	}
+
+	var obj session
+
+	require.EqualError(t,
+		decodeJSON(`{ "ttl": 9223372036854775807 }`, &obj),
+		"Error while decoding fauna value at: ttl. Can not decode 9223372036854775807 into a duration: Value out of range",
+	)
+
+	require.EqualError(t,
+		decodeJSON(`{ "ttl": -9300000000.5 }`, &obj),
+		"Error while decoding fauna value at: ttl. Can not decode -9.3000000005e+09 into a duration: Value out of range",
+	)
+}
+
+func TestDurationFieldsRoundTrip(t *testing.T) {
+	type session struct {
+		TTL    time.Duration `fauna:"ttl,seconds"`
+		Millis time.Duration `fauna:"millis,milliseconds"`
+	}
+
+	var obj session
+
+	value, err := ParseValue(strings.NewReader(toJSON(t, Obj{"data": session{90 * time.Minute, 1500 * time.Microsecond}})))
+	require.NoError(t, err)
+	require.NoError(t, value.At(ObjKey("object", "data", "object")).Get(&obj))
+	require.Equal(t, session{90 * time.Minute, 1500 * time.Microsecond}, obj)
+}
+
+func TestEpochFieldsRoundTrip(t *testing.T) {
+	type event struct {
+		Millis int64 `fauna:"millis,unixmilli"`
+	}
+
+	var obj event
+
+	value, err := ParseValue(strings.NewReader(toJSON(t, Obj{"data": event{1483264800123}})))
+	require.NoError(t, err)
+	require.NoError(t, value.At(ObjKey("object", "data", "object")).Get(&obj))
+	require.Equal(t, event{1483264800123}, obj)
+}
+
+func TestDeserializeStructWithBoolnumFields(t *testing.T) {
+	type user struct {
+		Active   bool `fauna:"active,boolnum"`
+		Disabled bool `fauna:"disabled,boolnum"`
+		Admin    bool `fauna:"admin,boolnum"`
+	}
+
+	var obj user
+
+	require.NoError(t, decodeJSON(`{ "active": 1, "disabled": 0, "admin": true }`, &obj))
+	require.Equal(t, user{Active: true, Disabled: false, Admin: true}, obj)
+}
+
+func TestFailToDeserializeInvalidBoolnumFields(t *testing.T) {
+	type user struct {
+		Active bool `fauna:"active,boolnum"`
+	}
+
+	var obj user
+
+	require.EqualError(t,
+		decodeJSON(`{ "active": 2 }`, &obj),
+		"Error while decoding fauna value at: active. Can not decode 2 into a boolnum field: Expected 0 or 1",
+	)
+}
+
+func TestDoNotDeserializeNumbersIntoBoolFieldsWithoutBoolnum(t *testing.T) {
+	type user struct {
+		Active bool `fauna:"active"`
+	}
+
+	var obj user
+
+	require.Error(t, decodeJSON(`{ "active": 1 }`, &obj))
+}
+
+func TestBoolnumFieldsRoundTrip(t *testing.T) {
+	type user struct {
+		Active   bool `fauna:"active,boolnum"`
+		Disabled bool `fauna:"disabled,boolnum"`
+	}
+
+	var obj user
+
+	value, err := ParseValue(strings.NewReader(toJSON(t, Obj{"data": user{true, false}})))
+	require.NoError(t, err)
+	require.NoError(t, value.At(ObjKey("object", "data", "object")).Get(&obj))
+	require.Equal(t, user{true, false}, obj)
+}
+
+func TestIgnoreEpochOptionOnNonIntegerFields(t *testing.T) {
+	type event struct {
+		Time time.Time `fauna:"time,unixmilli"`
+	}
+
+	var obj event
+
+	require.NoError(t, decodeJSON(`{ "time": { "@ts": "2017-01-01T10:00:00Z" } }`, &obj))
+	require.Equal(t, event{time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)}, obj)
+}
+
+func TestDeserializeStructWithSetRefFields(t *testing.T) {
+	type spellbook struct {
+		Name    string   `fauna:"name"`
+		Spells  SetRefV  `fauna:"spells"`
+		Pointer *SetRefV `fauna:"pointer"`
+		Value   Value    `fauna:"value"`
+	}
+
+	var obj spellbook
+
+	json := `
+	{
+		"name": "Necronomicon",
+		"spells": { "@set": { "match": { "@ref": "indexes/spells_by_book" }, "terms": "necronomicon" } },
+		"pointer": { "@set": { "match": { "@ref": "indexes/all_spells" } } },
+		"value": { "@set": { "match": { "@ref": "indexes/all_spells" } } }
+	}
+	`
+
+	allSpells := SetRefV{map[string]Value{"match": RefV{ID: "indexes/all_spells"}}}
+
+	require.NoError(t, decodeJSON(json, &obj))
+	require.Equal(t,
+		spellbook{
+			Name:    "Necronomicon",
+			Spells:  SetRefV{map[string]Value{"match": RefV{ID: "indexes/spells_by_book"}, "terms": StringV("necronomicon")}},
+			Pointer: &allSpells,
+			Value:   allSpells,
+		},
+		obj,
+	)
+}
+
+func TestDeserializeRegisteredEnums(t *testing.T) {
+	type spell struct {
+		Element  testElement   `fauna:"element"`
+		Elements []testElement `fauna:"elements"`
+		Number   testElement   `fauna:"number"`
+	}
+
+	var obj spell
+
+	require.NoError(t, decodeJSON(`{ "element": "water", "elements": ["fire", "water"], "number": 1 }`, &obj))
+	require.Equal(t, spell{testWater, []testElement{testFire, testWater}, testWater}, obj)
+}
+
+func TestFailToDeserializeUnknownEnumNames(t *testing.T) {
+	var element testElement
+
+	require.EqualError(t,
+		decodeJSON(`"earth"`, &element),
+		"Error while decoding fauna value at: <root>. Can not decode \"earth\" into enum \"faunadb.testElement\": Unknown name",
+	)
+}
+
+type testUUID [16]byte
+
+func (id *testUUID) UnmarshalText(text []byte) error {
+	hexID := strings.Replace(string(text), "-", "", -1)
+
+	if len(hexID) != 32 {
+		return errors.New("Invalid UUID length")
+	}
+
+	_, err := hex.Decode(id[:], []byte(hexID))
+	return err
+}
+
+func TestDeserializeTextUnmarshalers(t *testing.T) {
+	type session struct {
+		ID      testUUID   `fauna:"id"`
+		Parent  *testUUID  `fauna:"parent"`
+		Related []testUUID `fauna:"related"`
+		Address net.IP     `fauna:"address"`
+	}
+
+	var obj session
+
+	require.NoError(t,
+		decodeJSON(`{
+			"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			"parent": "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+			"related": ["6ba7b812-9dad-11d1-80b4-00c04fd430c8"],
+			"address": "192.168.0.1"
+		}`, &obj),
+	)
+
+	id := testUUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	parent, related := id, id
+	parent[3], related[3] = 0x11, 0x12
+
+	require.Equal(t, id, obj.ID)
+	require.Equal(t, &parent, obj.Parent)
+	require.Equal(t, []testUUID{related}, obj.Related)
+	require.Equal(t, net.ParseIP("192.168.0.1"), obj.Address)
+}
+
+func TestFailToDeserializeInvalidText(t *testing.T) {
+	var id testUUID
+
+	require.EqualError(t,
+		decodeJSON(`"6ba7b810"`, &id),
+		"Error while decoding fauna value at: <root>. Can not decode \"6ba7b810\" into a value of type \"faunadb.testUUID\": Invalid UUID length",
+	)
+}
+
+func TestDeserializeNonStringsIntoTextUnmarshalers(t *testing.T) {
+	var id testUUID
+
+	require.Error(t, decodeJSON(`42`, &id))
+}
+
+func TestDeserializeLargeStructSlice(t *testing.T) {
+	type spell struct {
+		Name  string `fauna:"name"`
+		Level int    `fauna:"level"`
+		Tags  []string
+	}
+
+	arr := make(ArrayV, 10000)
+	expected := make([]spell, len(arr))
+
+	for i := range arr {
+		name := fmt.Sprintf("spell-%d", i)
+		arr[i] = ObjectV{"name": StringV(name), "level": LongV(i), "Tags": ArrayV{StringV("fire")}}
+		expected[i] = spell{name, i, []string{"fire"}}
+	}
+
+	cachedFields := func() []fieldInfo {
+		structTypes.RLock()
+		defer structTypes.RUnlock()
+
+		return structTypes.fields[reflect.TypeOf(spell{})]
+	}
+
+	require.Nil(t, cachedFields())
+
+	var spells []spell
+	require.NoError(t, arr.Get(&spells))
+	require.Equal(t, expected, spells)
+
+	cached := cachedFields()
+	require.Len(t, cached, 3)
+
+	spells = nil
+	require.NoError(t, arr.Get(&spells))
+	require.Equal(t, expected, spells)
+
+	require.True(t, &cached[0] == &cachedFields()[0], "struct fields computed more than once")
+}
+
+func TestDeserializeStructWithRequiredFields(t *testing.T) {
+	type user struct {
+		Email    string `fauna:"email,required"`
+		Name     string `fauna:"name,required"`
+		Nickname string `fauna:"nickname"`
+	}
+
+	var obj user
+
+	require.NoError(t, decodeJSON(`{ "email": "jhon@example.com", "name": null }`, &obj))
+	require.Equal(t, user{Email: "jhon@example.com"}, obj)
+}
+
+func TestFailToDeserializeStructWithMissingRequiredFields(t *testing.T) {
+	type user struct {
+		Email    string `fauna:"email,required"`
+		Nickname string `fauna:"nickname"`
+		Name     string `fauna:"name,required"`
+		Age      int    `fauna:"age,required"`
+	}
+
+	var obj user
+
+	require.EqualError(t,
+		decodeJSON(`{ "nickname": "jj", "age": 24 }`, &obj),
+		"Error while decoding fauna value at: <root>. Missing required fields: email, name",
+	)
+}
+
+func TestFailToDeserializeNestedStructWithMissingRequiredFields(t *testing.T) {
+	type address struct {
+		City string `fauna:"city,required"`
+	}
+
+	type user struct {
+		Addresses []address `fauna:"addresses"`
+	}
+
+	var obj user
+
+	require.EqualError(t,
+		decodeJSON(`{ "addresses": [{ "city": "London" }, { "street": "Baker" }] }`, &obj),
+		"Error while decoding fauna value at: addresses / 1. Missing required fields: city",
+	)
+}
+
 func TestDeserializeStructWithIgnoredFields(t *testing.T) {
 	type object struct {
 		Name string `fauna:"name"`
@@ -296,6 +789,36 @@ func TestDeserializeStructWithPointers(t *testing.T) {
 	require.Equal(t, &object{&inner{"Jhon"}}, obj)
 }
 
+func TestDeserializeStructWithNullOrAbsentPointers(t *testing.T) {
+	type inner struct{ Name string }
+	type object struct {
+		Inner  *inner
+		Others []*inner
+	}
+
+	var withNull, absent object
+
+	require.NoError(t, decodeJSON(`{ "Inner": null, "Others": [ { "Name": "Jhon" }, null ] }`, &withNull))
+	require.Equal(t, object{Others: []*inner{{"Jhon"}, nil}}, withNull)
+
+	require.NoError(t, decodeJSON(`{ "Others": [] }`, &absent))
+	require.Nil(t, absent.Inner)
+}
+
+func TestDeserializeStructWithNestedPointers(t *testing.T) {
+	type leaf struct{ Value int }
+	type branch struct{ Leaf *leaf }
+	type tree struct{ Branch *branch }
+
+	var present, nullLeaf tree
+
+	require.NoError(t, decodeJSON(`{ "Branch": { "Leaf": { "Value": 42 } } }`, &present))
+	require.Equal(t, tree{&branch{&leaf{42}}}, present)
+
+	require.NoError(t, decodeJSON(`{ "Branch": { "Leaf": null } }`, &nullLeaf))
+	require.Equal(t, tree{&branch{}}, nullLeaf)
+}
+
 func TestDeserializeStructWithEmbeddedStructs(t *testing.T) {
 	type Embedded struct {
 		Str string
@@ -418,7 +941,7 @@ func TestDeserializeComplexStruct(t *testing.T) {
 	`
 	expected := complexStruct{
 		TaggedString: "TaggedString",
-		Ref:          RefV{"classes/spells/42"},
+		Ref:          RefV{ID: "classes/spells/42"},
 		Any:          StringV("any value"),
 		Date:         time.Date(1970, time.January, 3, 0, 0, 0, 0, time.UTC),
 		Time:         time.Date(1970, time.January, 1, 0, 0, 0, 5, time.UTC),
@@ -449,6 +972,58 @@ func TestDeserializeComplexStruct(t *testing.T) {
 	require.Equal(t, expected, object)
 }
 
+func TestDeserializeObjectToRawMessage(t *testing.T) {
+	var raw json.RawMessage
+
+	require.NoError(t, decodeJSON(`{"name": "fire", "data": {"@obj": {"@cost": 10}}, "ref": {"@ref": "classes/spells/42"}}`, &raw))
+	require.JSONEq(t, `{"name": "fire", "data": {"@cost": 10}, "ref": {"@ref": "classes/spells/42"}}`, string(raw))
+}
+
+func TestDeserializeArrayToRawMessage(t *testing.T) {
+	var raw json.RawMessage
+
+	require.NoError(t, decodeJSON(`[1, 2.5, "three", null, [{"nested": true}]]`, &raw))
+	require.Equal(t, `[1,2.5,"three",null,[{"nested":true}]]`, string(raw))
+}
+
+func TestDeserializeFieldToRawMessage(t *testing.T) {
+	var doc struct {
+		Name string          `fauna:"name"`
+		Data json.RawMessage `fauna:"data"`
+	}
+
+	require.NoError(t, decodeJSON(`{"name": "fire", "data": {"elements": ["fire", "air"]}}`, &doc))
+	require.Equal(t, "fire", doc.Name)
+	require.Equal(t, `{"elements":["fire","air"]}`, string(doc.Data))
+}
+
+func TestParseValue(t *testing.T) {
+	value, err := ParseValue(strings.NewReader(`{"resource": {"ref": {"@ref": "classes/spells/42"}, "ts": 1}}`))
+
+	require.NoError(t, err)
+	require.Equal(t,
+		ObjectV{"resource": ObjectV{"ref": RefV{ID: "classes/spells/42"}, "ts": LongV(1)}},
+		value,
+	)
+}
+
+func TestParseResponse(t *testing.T) {
+	value, err := ParseResponse(strings.NewReader(`{"resource": {"ref": {"@ref": "classes/spells/42"}, "ts": 1}}`))
+
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"ref": RefV{ID: "classes/spells/42"}, "ts": LongV(1)}, value)
+}
+
+func TestParseResponseWithoutResource(t *testing.T) {
+	_, err := ParseResponse(strings.NewReader(`{"errors": []}`))
+	require.EqualError(t, err, "Error while extracting path: resource. Object key resource not found")
+}
+
+func TestParseInvalidValue(t *testing.T) {
+	_, err := ParseValue(strings.NewReader(`{"resource": `))
+	require.Error(t, err)
+}
+
 func decodeJSON(raw string, target interface{}) (err error) {
 	buffer := []byte(raw)
 
@@ -29,6 +29,32 @@ If you wish to control property names, you can tag them with "fauna" tag:
 
 	user := User{"Jhon", 24} // Encode as: {"displayName": "Jhon", "age": 24}
 
+Options can follow the property name, separated by commas. Integer fields tagged with unix, unixmilli, or unixmicro
+hold timestamps as the number of seconds, milliseconds, or microseconds since the Unix epoch, while time.Duration
+fields tagged with seconds, milliseconds, microseconds, or nanoseconds are stored as numbers of that unit. Untagged
+time.Duration fields, like time.Duration values decoded or encoded outside of structs, are stored as their number of
+nanoseconds:
+
+	type Session struct {
+		CreatedAt int64         `fauna:"created_at,unixmilli"` // Encode as: {"@ts": "2017-01-01T10:00:00.123Z"}
+		TTL       time.Duration `fauna:"ttl,seconds"`          // Encode as: 3600 for time.Hour
+		Timeout   time.Duration `fauna:"timeout,milliseconds"` // Encode as: 1500 for 1.5 seconds
+		Elapsed   time.Duration `fauna:"elapsed"`              // Encode as: 1000000 for a millisecond
+	}
+
+Boolean fields tagged with boolnum are stored as the numbers 0 and 1, as some legacy data does:
+
+	type User struct {
+		Active bool `fauna:"active,boolnum"` // Encode as: 1 for true
+	}
+
+Fields tagged with required must be present when decoding objects into the struct. Decoding fails with an error
+listing every required field missing from the object, instead of leaving them with their zero values:
+
+	type User struct {
+		Email string `fauna:"email,required"`
+	}
+
 For more information about FaunaDB, check https://fauna.com/.
 */
 package faunadb
@@ -33,6 +33,10 @@ func wrap(i interface{}) Expr {
 		return value.Interface().(Expr)
 	}
 
+	if enum, found := lookupEnum(valueType); found {
+		return enum.encode(value)
+	}
+
 	switch kind {
 	case reflect.String:
 		return StringV(value.String())
@@ -97,3 +101,37 @@ func wrapArray(value reflect.Value) Expr {
 
 	return arr
 }
+
+func encodeStructField(field structField) interface{} {
+	if unit, ok := field.options.durationUnit(); ok && field.value.Type() == durationType {
+		duration := time.Duration(field.value.Int())
+
+		if duration%unit == 0 {
+			return LongV(duration / unit)
+		}
+
+		return DoubleV(float64(duration) / float64(unit))
+	}
+
+	if unit, ok := field.options.epochUnit(); ok && isIntegerKind(field.value.Kind()) {
+		var units int64
+
+		if field.value.Kind() >= reflect.Uint && field.value.Kind() <= reflect.Uint64 {
+			units = int64(field.value.Uint())
+		} else {
+			units = field.value.Int()
+		}
+
+		return TimeV(timeFromEpoch(units, unit))
+	}
+
+	if field.options.has(boolnumOption) && field.value.Kind() == reflect.Bool {
+		if field.value.Bool() {
+			return LongV(1)
+		}
+
+		return LongV(0)
+	}
+
+	return field.value.Interface()
+}
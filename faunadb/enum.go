@@ -0,0 +1,91 @@
+package faunadb
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var enums = struct {
+	sync.RWMutex
+	mappings map[reflect.Type]enumMapping
+}{mappings: make(map[reflect.Type]enumMapping)}
+
+type enumMapping struct {
+	names  map[int64]string
+	values map[string]int64
+}
+
+/*
+RegisterEnum registers the string representation of the values of an integer based enum type, informed by any of
+its values. Once registered, values of the enum type are encoded as their names, and names are decoded back into
+values of the enum type. Encoding or decoding values not present in the names informed fails. For example:
+
+	type Element int
+
+	const (
+		Fire Element = iota
+		Water
+	)
+
+	RegisterEnum(Fire, map[int]string{int(Fire): "fire", int(Water): "water"})
+
+	client.Query(Obj{"element": Water}) // Encode as: {"element": "water"}
+
+Registering the same type again replaces its previous names. RegisterEnum panics if the type is not integer based.
+*/
+func RegisterEnum(enum interface{}, names map[int]string) {
+	enumType := reflect.TypeOf(enum)
+
+	if enumType == nil || !isIntegerKind(enumType.Kind()) {
+		panic(fmt.Sprintf("Error while registering enum: Expected an integer based type but got %T", enum))
+	}
+
+	mapping := enumMapping{
+		names:  make(map[int64]string, len(names)),
+		values: make(map[string]int64, len(names)),
+	}
+
+	for value, name := range names {
+		mapping.names[int64(value)] = name
+		mapping.values[name] = int64(value)
+	}
+
+	enums.Lock()
+	enums.mappings[enumType] = mapping
+	enums.Unlock()
+}
+
+func lookupEnum(enumType reflect.Type) (mapping enumMapping, found bool) {
+	enums.RLock()
+	mapping, found = enums.mappings[enumType]
+	enums.RUnlock()
+
+	return
+}
+
+func (mapping enumMapping) encode(value reflect.Value) Expr {
+	var num int64
+
+	if value.Kind() >= reflect.Uint && value.Kind() <= reflect.Uint64 {
+		num = int64(value.Uint())
+	} else {
+		num = value.Int()
+	}
+
+	if name, found := mapping.names[num]; found {
+		return StringV(name)
+	}
+
+	return invalidExpr{fmt.Errorf("Error while encoding enum %s: Unknown value %d", value.Type(), num)}
+}
+
+func (c *valueDecoder) assignEnum(mapping enumMapping, str StringV) error {
+	num, found := mapping.values[string(str)]
+
+	if !found {
+		return DecodeError{err: fmt.Errorf("Can not decode \"%s\" into enum \"%s\": Unknown name", str, c.targetType)}
+	}
+
+	return c.assign(LongV(num))
+}
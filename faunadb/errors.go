@@ -1,13 +1,21 @@
 package faunadb
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var errorsField = ObjKey("errors")
 
+// maxErrorResponseBytes limits how much of an error response body is read, so pathological error responses, such as
+// the ones of misbehaving proxies, can not exhaust memory. Errors must be found within the limit to be parsed.
+const maxErrorResponseBytes = 64 * 1024
+
 // A FaunaError wraps HTTP errors when sending queries to a FaunaDB cluster.
 type FaunaError interface {
 	error
@@ -36,6 +44,45 @@ type Unavailable struct{ FaunaError }
 // A UnknownError wraps any unknown http error response.
 type UnknownError struct{ FaunaError }
 
+// A ResponseTooLargeError is returned when a response body exceeds the limit set by the MaxResponseBytes configuration.
+type ResponseTooLargeError struct {
+	Limit int64 // Maximum number of bytes allowed
+}
+
+func (err ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("Response body exceeds the limit of %d bytes", err.Limit)
+}
+
+// A ConcurrencyLimitError is returned when a query waits longer than the client's Timeout for one of the requests
+// allowed by the MaxConcurrency configuration to finish.
+type ConcurrencyLimitError struct {
+	Limit   int           // Maximum number of requests in flight
+	Timeout time.Duration // Time spent waiting
+}
+
+func (err ConcurrencyLimitError) Error() string {
+	return fmt.Sprintf("Error while sending request: Timed out after %s waiting for one of %d requests in flight", err.Timeout, err.Limit)
+}
+
+// An EmptyResponseError is returned when FaunaDB, or a proxy in front of it, replies with an empty body.
+type EmptyResponseError struct {
+	Status int // HTTP status code
+}
+
+func (err EmptyResponseError) Error() string {
+	return fmt.Sprintf("Error while parsing response: Empty body with HTTP status %d", err.Status)
+}
+
+// An InvalidResponseError is returned when FaunaDB, or a proxy in front of it, replies with a body that is not valid JSON.
+type InvalidResponseError struct {
+	Status int   // HTTP status code
+	Cause  error // JSON syntax error found in the body
+}
+
+func (err InvalidResponseError) Error() string {
+	return fmt.Sprintf("Error while parsing response: Invalid JSON body with HTTP status %d. %s", err.Status, err.Cause)
+}
+
 // QueryError describes query errors returned by the server.
 type QueryError struct {
 	Position    []string            `fauna:"position"`
@@ -44,6 +91,32 @@ type QueryError struct {
 	Failures    []ValidationFailure `fauna:"failures"`
 }
 
+/*
+ExprIndex returns the index of the expression that caused the error in a query composed of multiple expressions,
+such as the expressions informed to BatchQuery or to the Do function. It is parsed from the error position:
+for example, an error at position ["do", "2", "create"] was caused by the third expression of the Do function.
+The boolean returned is false if the position does not point to one of the expressions.
+*/
+func (queryError QueryError) ExprIndex() (int, bool) {
+	position := queryError.Position
+
+	if len(position) > 0 && position[0] == "do" {
+		position = position[1:]
+
+		if len(position) == 0 {
+			return 0, true // A Do function with a single expression
+		}
+	}
+
+	if len(position) > 0 {
+		if index, err := strconv.Atoi(position[0]); err == nil && index >= 0 {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
 // ValidationFailure describes validation errors on a submitted query.
 type ValidationFailure struct {
 	Field       []string `fauna:"field"`
@@ -107,8 +180,9 @@ func parseErrorResponse(response *http.Response) FaunaError {
 	var errors []QueryError
 
 	if response.Body != nil {
-		if value, err := parseJSON(response.Body); err == nil {
+		if value, err := parseErrors(io.LimitReader(response.Body, maxErrorResponseBytes)); err == nil {
 			if err := value.At(errorsField).Get(&errors); err == nil {
+				decodePositions(value, errors)
 				return errorResponse{true, response.StatusCode, errors}
 			}
 		}
@@ -116,3 +190,58 @@ func parseErrorResponse(response *http.Response) FaunaError {
 
 	return errorResponse{false, response.StatusCode, errors}
 }
+
+// parseErrors parses the "errors" key of an error response body, returning an object with that key only.
+// The keys after it are not read, so errors can be parsed from bodies truncated after them.
+func parseErrors(reader io.Reader) (Value, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	parser := jsonParser{decoder}
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, wrongToken{"an object", token}
+	}
+
+	for decoder.More() {
+		key, err := parser.readString()
+		if err != nil {
+			return nil, err
+		}
+
+		if key == "errors" {
+			errors, err := parser.parseNext()
+			return ObjectV{"errors": errors}, err
+		}
+
+		var ignored json.RawMessage
+		if err := decoder.Decode(&ignored); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, ValueNotFound{pathFromKeys("errors"), segmentNotFound{"Object key", objectSegment("errors")}}
+}
+
+// decodePositions decodes the positions of the errors informed as strings, keeping array indexes, which are numbers
+// in the response, as their decimal representation.
+func decodePositions(value Value, errors []QueryError) {
+	for i := range errors {
+		var position []Value
+
+		if value.At(errorsField.AtIndex(i).AtKey("position")).Get(&position) != nil {
+			continue
+		}
+
+		errors[i].Position = make([]string, len(position))
+
+		for j, elem := range position {
+			switch elem := elem.(type) {
+			case LongV:
+				errors[i].Position[j] = strconv.FormatInt(int64(elem), 10)
+			case StringV:
+				errors[i].Position[j] = string(elem)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -94,6 +95,56 @@ func TestParseErrorResponse(t *testing.T) {
 	require.EqualError(t, err, "Response error 401. Errors: [data/token](invalid token): Invalid token.")
 }
 
+func TestParseNumericErrorPositions(t *testing.T) {
+	json := `{"errors": [{"position": ["do", 10, "create"], "code": "instance not unique", "description": "Not unique."}]}`
+	err := checkForResponseErrors(httpErrorResponseWith(400, json))
+
+	require.Equal(t, []string{"do", "10", "create"}, err.(BadRequest).Errors()[0].Position)
+	require.EqualError(t, err, "Response error 400. Errors: [do/10/create](instance not unique): Not unique.")
+}
+
+func TestExprIndex(t *testing.T) {
+	for _, test := range []struct {
+		position []string
+		index    int
+		ok       bool
+	}{
+		{[]string{"2", "create"}, 2, true},
+		{[]string{"do", "1", "params"}, 1, true},
+		{[]string{"do"}, 0, true},
+		{[]string{"do", "create"}, 0, false},
+		{[]string{"data", "token"}, 0, false},
+		{[]string{"-1"}, 0, false},
+		{[]string{}, 0, false},
+	} {
+		index, ok := QueryError{Position: test.position}.ExprIndex()
+		require.Equal(t, test.ok, ok, "%v", test.position)
+		require.Equal(t, test.index, index, "%v", test.position)
+	}
+}
+
+func TestExprIndexOfConflictingWriteInBatch(t *testing.T) {
+	body := `{"errors": [{"position": [1, "create"], "code": "instance not unique",` +
+		` "description": "Instance is not unique."}]}`
+
+	client := NewFaunaClientWithTransport("secret", RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		response := httpErrorResponseWith(400, body)
+		response.Request = request
+		return response, nil
+	}))
+
+	_, err := client.BatchQuery([]Expr{
+		Create(Class("spells"), Obj{"data": Obj{"name": "Fireball"}}),
+		Create(Class("spells"), Obj{"data": Obj{"name": "Fireball"}}),
+	})
+
+	require.IsType(t, BadRequest{}, err)
+
+	index, ok := err.(BadRequest).Errors()[0].ExprIndex()
+	require.True(t, ok)
+	require.Equal(t, 1, index)
+}
+
 func TestUnparseableResponse(t *testing.T) {
 	json := "can't parse this as an error"
 	err := checkForResponseErrors(httpErrorResponseWith(503, json))
@@ -102,6 +153,25 @@ func TestUnparseableResponse(t *testing.T) {
 	require.EqualError(t, err, "Response error 503. Unparseable server response.")
 }
 
+func TestParseErrorsBeforeOversizedContent(t *testing.T) {
+	body := `{"errors": [{"position": [], "code": "unavailable", "description": "Proxy error."}], ` +
+		`"detail": "` + strings.Repeat("x", 10*maxErrorResponseBytes) + `"}`
+
+	err := checkForResponseErrors(httpErrorResponseWith(503, body))
+
+	require.EqualError(t, err, "Response error 503. Errors: [](unavailable): Proxy error.")
+}
+
+func TestBoundOversizedErrorResponseReads(t *testing.T) {
+	body := `{"detail": "` + strings.Repeat("x", 10*maxErrorResponseBytes) + `", "errors": []}`
+	buffer := bytes.NewBufferString(body)
+
+	err := checkForResponseErrors(&http.Response{StatusCode: 502, Body: ioutil.NopCloser(buffer)})
+
+	require.Equal(t, UnknownError{errorResponse{status: 502}}, err)
+	require.Equal(t, maxErrorResponseBytes, len(body)-buffer.Len())
+}
+
 func httpErrorResponseWith(status int, errorBody string) *http.Response {
 	return &http.Response{
 		StatusCode: status,
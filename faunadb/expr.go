@@ -1,7 +1,5 @@
 package faunadb
 
-import "encoding/json"
-
 /*
 Expr represents FaunaDB query language expressions.
 
@@ -27,10 +25,16 @@ type Expr interface {
 type unescapedObj map[string]Expr
 type unescapedArr []Expr
 type invalidExpr struct{ err error }
+type rawExpr []byte
 
 func (obj unescapedObj) expr() {}
 func (arr unescapedArr) expr() {}
 func (inv invalidExpr) expr()  {}
+func (raw rawExpr) expr()      {}
+
+func (raw rawExpr) MarshalJSON() ([]byte, error) {
+	return raw, nil
+}
 
 func (inv invalidExpr) MarshalJSON() ([]byte, error) {
 	return nil, inv.err
@@ -46,10 +50,24 @@ func (obj Obj) expr() {}
 func (arr Arr) expr() {}
 
 // MarshalJSON implements json.Marshaler for Obj expression
-func (obj Obj) MarshalJSON() ([]byte, error) { return json.Marshal(wrap(obj)) }
+func (obj Obj) MarshalJSON() ([]byte, error) { return marshalJSON(wrap(obj)) }
 
 // MarshalJSON implements json.Marshaler for Arr expression
-func (arr Arr) MarshalJSON() ([]byte, error) { return json.Marshal(wrap(arr)) }
+func (arr Arr) MarshalJSON() ([]byte, error) { return marshalJSON(wrap(arr)) }
+
+// ExprJSON returns the JSON encoding of the expression informed, exactly as FaunaClient sends it in query requests.
+// Like query requests, HTML characters such as <, >, and & are not escaped on Go 1.7 or later. It returns the
+// encoding errors reported by the expression, such as the ones of invalid enum values.
+func ExprJSON(expr Expr) ([]byte, error) { return marshalJSON(expr) }
+
+// BoundVar is a variable bound by the LetFn function. It can be used as an expression that refers to its
+// bound value, the same way as a Var expression with the variable name.
+type BoundVar struct{ name *string }
+
+func (v BoundVar) expr() {}
+
+// MarshalJSON implements json.Marshaler by escaping the variable as a Var expression.
+func (v BoundVar) MarshalJSON() ([]byte, error) { return marshalJSON(Var(*v.name)) }
 
 // OptionalParameter describes optional parameters for query language functions
 type OptionalParameter func(unescapedObj)
@@ -2,9 +2,13 @@ package faunadb
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -85,3 +89,70 @@ func GetSecret(key Value) (secret string) {
 
 	return
 }
+
+// mockServer replies to each request with the next response informed, repeating the last one when exhausted.
+// Request bodies are recorded so tests can assert what was sent to the server.
+type mockServer struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	headers   http.Header
+	responses []string
+	requests  []*http.Request
+	bodies    []string
+}
+
+func newMockServer(responses ...string) *mockServer {
+	mock := &mockServer{responses: responses}
+	mock.Server = httptest.NewServer(http.HandlerFunc(mock.serve))
+
+	return mock
+}
+
+// newMockServerWithHeaders works as newMockServer, adding the headers informed to every response.
+func newMockServerWithHeaders(headers http.Header, responses ...string) *mockServer {
+	mock := newMockServer(responses...)
+	mock.headers = headers
+
+	return mock
+}
+
+func (mock *mockServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	mock.mutex.Lock()
+	next := len(mock.requests)
+	if next >= len(mock.responses) {
+		next = len(mock.responses) - 1
+	}
+
+	response := mock.responses[next]
+	mock.requests = append(mock.requests, r)
+	mock.bodies = append(mock.bodies, string(body))
+	mock.mutex.Unlock()
+
+	for key, values := range mock.headers {
+		w.Header()[key] = values
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write([]byte(response))
+}
+
+func (mock *mockServer) client(configs ...ClientConfig) *FaunaClient {
+	return NewFaunaClient("secret", append([]ClientConfig{Endpoint(mock.URL)}, configs...)...)
+}
+
+func (mock *mockServer) requestBodies() []string {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	return append([]string{}, mock.bodies...)
+}
+
+func (mock *mockServer) requestHeader(i int) http.Header {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	return mock.requests[i].Header
+}
@@ -5,16 +5,42 @@ type Field struct{ path path }
 
 // FieldValue describes an extracted field value.
 type FieldValue interface {
-	GetValue() (Value, error) // GetValue returns the extracted FaunaDB value.
-	Get(i interface{}) error  // Get decodes a FaunaDB value to a native Go type.
+	GetValue() (Value, error)  // GetValue returns the extracted FaunaDB value.
+	Get(i interface{}) error   // Get decodes a FaunaDB value to a native Go type.
+	At(field Field) FieldValue // At transverses the extracted value using the field extractor informed.
+
+	AsString() (StringV, error) // AsString returns the extracted value if it is a string, otherwise an InvalidFieldType error.
+	AsArray() (ArrayV, error)   // AsArray returns the extracted value if it is an array, otherwise an InvalidFieldType error.
+	AsObject() (ObjectV, error) // AsObject returns the extracted value if it is an object, otherwise an InvalidFieldType error.
+
+	// Default returns a field holding the value informed if the extracted value was not found, like the Default
+	// optional parameter of the Select function. Other errors, such as an InvalidFieldType, are kept.
+	Default(value Value) FieldValue
 }
 
 // ObjKey creates a field extractor for a JSON object based on the keys informed.
 func ObjKey(keys ...string) Field { return Field{pathFromKeys(keys...)} }
 
 // ArrIndex creates a field extractor for a JSON array based on the indexes informed.
+// Negative indexes count from the end of the array: -1 is the last element. Indexes out of
+// the array bounds fail with a ValueNotFound error, which can be recovered with FieldValue.Default.
 func ArrIndex(indexes ...int) Field { return Field{pathFromIndexes(indexes...)} }
 
+// AnyIndex creates a field extractor based on the indexes informed that adapts to the value being transversed:
+// arrays are indexed by position, while objects are indexed by keys holding the index as a string, such as "0".
+func AnyIndex(indexes ...int) Field { return Field{pathFromAnyIndexes(indexes...)} }
+
+/*
+SelectPath creates a field extractor based on a path with the same semantics as the Select function, allowing
+the same path to be used on both the server and decoded values. Strings are object keys, while integers are array
+indexes. Negative indexes count from the end of the array: -1 is the last element. For example:
+
+	SelectPath("data", "emails", -1) // Same as ObjKey("data", "emails").AtIndex(-1)
+
+Path elements of any other type fail the extraction.
+*/
+func SelectPath(path ...interface{}) Field { return Field{pathFromElements(path...)} }
+
 // At creates a new field extractor based on the sub field informed.
 func (f Field) At(other Field) Field { return Field{f.path.subPath(other.path)} }
 
@@ -24,22 +50,72 @@ func (f Field) AtKey(keys ...string) Field { return f.At(ObjKey(keys...)) }
 // AtIndex creates a new field extractor based on the sub index informed.
 func (f Field) AtIndex(indexes ...int) Field { return f.At(ArrIndex(indexes...)) }
 
-func (f *Field) get(value Value) FieldValue {
-	value, err := f.path.get(value)
+// AtAnyIndex creates a new field extractor based on the sub indexes informed. See AnyIndex.
+func (f Field) AtAnyIndex(indexes ...int) Field { return f.At(AnyIndex(indexes...)) }
+
+func (f *Field) get(value Value) FieldValue { return validField{value: value}.At(*f) }
+
+type validField struct {
+	path  path
+	value Value
+}
+
+func (v validField) GetValue() (Value, error) { return v.value, nil }
+func (v validField) Get(i interface{}) error  { return v.value.Get(i) }
+
+func (v validField) At(field Field) FieldValue {
+	value, err := field.path.extract(v.path, v.value)
 
 	if err != nil {
 		return invalidField{err}
 	}
 
-	return validField{value}
+	return validField{v.path.subPath(field.path), value}
 }
 
-type validField struct{ value Value }
+func (v validField) AsString() (StringV, error) {
+	if str, ok := v.value.(StringV); ok {
+		return str, nil
+	}
 
-func (v validField) GetValue() (Value, error) { return v.value, nil }
-func (v validField) Get(i interface{}) error  { return v.value.Get(i) }
+	return "", v.invalidType("a string")
+}
+
+func (v validField) AsArray() (ArrayV, error) {
+	if arr, ok := v.value.(ArrayV); ok {
+		return arr, nil
+	}
+
+	return nil, v.invalidType("an array")
+}
+
+func (v validField) AsObject() (ObjectV, error) {
+	if obj, ok := v.value.(ObjectV); ok {
+		return obj, nil
+	}
+
+	return nil, v.invalidType("an object")
+}
+
+func (v validField) Default(value Value) FieldValue { return v }
+
+func (v validField) invalidType(desired string) error {
+	return InvalidFieldType{v.path, invalidSegmentType{desired, v.value}}
+}
 
 type invalidField struct{ err error }
 
-func (v invalidField) GetValue() (Value, error) { return nil, v.err }
-func (v invalidField) Get(i interface{}) error  { return v.err }
+func (v invalidField) GetValue() (Value, error)   { return nil, v.err }
+func (v invalidField) Get(i interface{}) error    { return v.err }
+func (v invalidField) At(field Field) FieldValue  { return v }
+func (v invalidField) AsString() (StringV, error) { return "", v.err }
+func (v invalidField) AsArray() (ArrayV, error)   { return nil, v.err }
+func (v invalidField) AsObject() (ObjectV, error) { return nil, v.err }
+
+func (v invalidField) Default(value Value) FieldValue {
+	if notFound, ok := v.err.(ValueNotFound); ok {
+		return validField{notFound.path, value}
+	}
+
+	return v
+}
@@ -46,7 +46,7 @@ func TestFailToExtractFieldForNonTransversableValues(t *testing.T) {
 		BooleanV(false),
 		DateV(time.Now()),
 		TimeV(time.Now()),
-		RefV{"classes/spells"},
+		RefV{ID: "classes/spells"},
 		SetRefV{map[string]Value{"any": StringV("set")}},
 		NullV{},
 	)
@@ -78,6 +78,189 @@ func TestReportErrorPathWhenValueIsNotAnObject(t *testing.T) {
 		"Error while extracting path: 0 / testField. Expected value to be an object but was a faunadb.ArrayV")
 }
 
+func TestExtractValueInMultipleSteps(t *testing.T) {
+	var str string
+
+	value := ObjectV{
+		"data": ObjectV{
+			"array": ArrayV{ObjectV{"testField": StringV("A")}},
+		},
+	}
+
+	data := value.At(ObjKey("data"))
+	err := data.At(ObjKey("array")).At(ArrIndex(0).AtKey("testField")).Get(&str)
+
+	require.NoError(t, err)
+	require.Equal(t, "A", str)
+}
+
+func TestReportFullPathOnMultipleStepsExtraction(t *testing.T) {
+	value := ObjectV{"data": ObjectV{"testField": StringV("A")}}
+
+	_, err := value.At(ObjKey("data")).At(ObjKey("testField")).At(ArrIndex(1)).GetValue()
+	require.EqualError(t, err,
+		"Error while extracting path: data / testField / 1. Expected value to be an array but was a faunadb.StringV")
+}
+
+func TestPropagateErrorOnMultipleStepsExtraction(t *testing.T) {
+	value := ObjectV{"data": ObjectV{}}
+
+	field := value.At(ObjKey("data", "missing")).At(ObjKey("testField"))
+
+	_, err := field.GetValue()
+	require.EqualError(t, err, "Error while extracting path: data / missing. Object key missing not found")
+}
+
+func TestExtractTypedValues(t *testing.T) {
+	value := ObjectV{"data": ObjectV{"name": StringV("Fire"), "tags": ArrayV{StringV("hot")}}}
+	data := value.At(ObjKey("data"))
+
+	obj, err := data.AsObject()
+	require.NoError(t, err)
+	require.Equal(t, value["data"], obj)
+
+	str, err := data.At(ObjKey("name")).AsString()
+	require.NoError(t, err)
+	require.Equal(t, StringV("Fire"), str)
+
+	arr, err := data.At(ObjKey("tags")).AsArray()
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{StringV("hot")}, arr)
+}
+
+func TestFailToExtractTypedValuesOfWrongType(t *testing.T) {
+	value := ObjectV{"data": ObjectV{"name": StringV("Fire"), "level": LongV(3)}}
+
+	_, err := value.At(ObjKey("data", "level")).AsString()
+	require.EqualError(t, err, "Error while extracting path: data / level. Expected value to be a string but was a faunadb.LongV")
+
+	_, err = value.At(ObjKey("data", "name")).AsArray()
+	require.EqualError(t, err, "Error while extracting path: data / name. Expected value to be an array but was a faunadb.StringV")
+
+	_, err = value.At(ObjKey("data")).At(ObjKey("name")).AsObject()
+	require.EqualError(t, err, "Error while extracting path: data / name. Expected value to be an object but was a faunadb.StringV")
+
+	_, err = value.At(ArrIndex()).AsArray()
+	require.IsType(t, InvalidFieldType{}, err)
+}
+
+func TestPropagateErrorOnTypedExtraction(t *testing.T) {
+	value := ObjectV{}
+
+	_, err := value.At(ObjKey("missing")).AsString()
+	require.EqualError(t, err, "Error while extracting path: missing. Object key missing not found")
+}
+
+func TestExtractAnyIndexFromArraysAndObjects(t *testing.T) {
+	arr := ArrayV{StringV("zero"), ArrayV{StringV("one"), StringV("two")}}
+	obj := ObjectV{"0": StringV("zero"), "1": ObjectV{"0": StringV("one"), "1": StringV("two")}}
+	mixed := ObjectV{"1": ArrayV{StringV("one"), StringV("two")}}
+
+	for _, value := range []Value{arr, obj, mixed} {
+		var str string
+
+		require.NoError(t, value.At(AnyIndex(1, 1)).Get(&str))
+		require.Equal(t, "two", str)
+
+		require.NoError(t, value.At(AnyIndex(1).AtAnyIndex(0)).Get(&str))
+		require.Equal(t, "one", str)
+	}
+}
+
+func TestFailToExtractAnyIndex(t *testing.T) {
+	assertFailToExtractField(t, ArrayV{}, AnyIndex(1),
+		"Error while extracting path: 1. Array index 1 not found")
+
+	assertFailToExtractField(t, ObjectV{"0": NullV{}}, AnyIndex(1),
+		"Error while extracting path: 1. Object key 1 not found")
+
+	assertFailToExtractField(t, ObjectV{"0": StringV("zero")}, AnyIndex(0, 1),
+		"Error while extracting path: 0 / 1. Expected value to be an array or an object but was a faunadb.StringV")
+}
+
+func TestExtractSelectPath(t *testing.T) {
+	value := ObjectV{"data": ObjectV{"emails": ArrayV{StringV("a@fauna.com"), StringV("b@fauna.com"), StringV("c@fauna.com")}}}
+
+	for expected, path := range map[string][]interface{}{
+		"a@fauna.com": {"data", "emails", 0},
+		"b@fauna.com": {"data", "emails", int64(-2)},
+		"c@fauna.com": {"data", "emails", -1},
+	} {
+		var email string
+
+		require.NoError(t, value.AtPath(path...).Get(&email))
+		require.Equal(t, expected, email)
+
+		require.NoError(t, value.At(SelectPath(path...)).Get(&email))
+		require.Equal(t, expected, email)
+	}
+}
+
+func TestExtractNegativeIndexes(t *testing.T) {
+	value := ArrayV{LongV(1), LongV(2), LongV(3)}
+
+	var num int
+
+	require.NoError(t, value.At(ArrIndex(-1)).Get(&num))
+	require.Equal(t, 3, num)
+
+	require.NoError(t, value.At(ArrIndex(-3)).Get(&num))
+	require.Equal(t, 1, num)
+
+	assertFailToExtractField(t, value, ArrIndex(-4), "Error while extracting path: -4. Array index -4 not found")
+}
+
+func TestExtractIndexesWithDefault(t *testing.T) {
+	value := ObjectV{"data": ArrayV{LongV(1), LongV(2), LongV(3)}}
+
+	var num int
+
+	require.NoError(t, value.At(ObjKey("data").AtIndex(-1)).Default(LongV(0)).Get(&num))
+	require.Equal(t, 3, num)
+
+	require.NoError(t, value.At(ObjKey("data").AtIndex(1)).Default(LongV(0)).Get(&num))
+	require.Equal(t, 2, num)
+
+	require.NoError(t, value.At(ObjKey("data").AtIndex(3)).Default(LongV(0)).Get(&num))
+	require.Equal(t, 0, num)
+
+	require.NoError(t, value.At(ObjKey("data").AtIndex(-4)).Default(LongV(-1)).Get(&num))
+	require.Equal(t, -1, num)
+
+	_, err := value.At(ObjKey("data").AtIndex(3)).GetValue()
+	require.IsType(t, ValueNotFound{}, err)
+}
+
+func TestDefaultValuesCanBeTransversed(t *testing.T) {
+	value := ObjectV{}
+
+	var name string
+
+	require.NoError(t, value.At(ObjKey("data")).Default(ObjectV{"name": StringV("none")}).At(ObjKey("name")).Get(&name))
+	require.Equal(t, "none", name)
+}
+
+func TestDoNotDefaultInvalidFieldTypes(t *testing.T) {
+	value := ObjectV{"data": StringV("not an array")}
+
+	_, err := value.At(ObjKey("data").AtIndex(0)).Default(LongV(0)).GetValue()
+	require.EqualError(t, err, "Error while extracting path: data / 0. Expected value to be an array but was a faunadb.StringV")
+}
+
+func TestFailToExtractSelectPath(t *testing.T) {
+	value := ObjectV{"data": ArrayV{StringV("a")}}
+
+	_, err := value.AtPath("data", 1.5).GetValue()
+	require.IsType(t, InvalidPathElement{}, err)
+	require.EqualError(t, err, "Error while extracting path: data / 1.5. Expected path element to be a string or an integer but was a float64")
+
+	_, err = value.AtPath("data", "name").GetValue()
+	require.EqualError(t, err, "Error while extracting path: data / name. Expected value to be an object but was a faunadb.ArrayV")
+
+	_, err = StringV("a").AtPath(0).GetValue()
+	require.EqualError(t, err, "Error while extracting path: 0. Expected value to be an array but was a faunadb.StringV")
+}
+
 func assertFailToExtractField(t *testing.T, value Value, field Field, message string) {
 	_, err := value.At(field).GetValue()
 	require.EqualError(t, err, message)
@@ -0,0 +1,41 @@
+package faunadb
+
+import "errors"
+
+var errFQLEndpointNotConfigured = errors.New("Error while sending FQL query: FQL endpoint is not configured")
+
+// FQLEndpoint configures the FaunaClient structure to send the queries of QueryFQL to the url informed, for FaunaDB
+// clusters exposing an endpoint for queries written as FQL text. QueryFQL fails when no FQL endpoint is configured.
+func FQLEndpoint(endpoint string) ClientConfig {
+	return func(cli *FaunaClient) { cli.fqlEndpoint = endpoint }
+}
+
+/*
+QueryFQL sends a query written as FQL text to the client's FQLEndpoint, instead of a query language expression.
+The arguments informed are encoded as expressions are, so they can be any value accepted by the query functions, and
+are sent along with the query under their names. For example:
+
+	client.QueryFQL("Spells.byName(name)", map[string]interface{}{"name": "Fireball"})
+
+Sends the following request body:
+
+	{"arguments":{"name":"Fireball"},"query":"Spells.byName(name)"}
+
+The value returned is the one under the "data" key of the response. It accepts the same configurations as Query,
+and failed queries report the same errors.
+*/
+func (client *FaunaClient) QueryFQL(fql string, args map[string]interface{}, configs ...QueryConfig) (Value, error) {
+	if client.fqlEndpoint == "" {
+		return nil, errFQLEndpointNotConfigured
+	}
+
+	arguments := make(unescapedObj, len(args))
+
+	for name, arg := range args {
+		arguments[name] = wrap(arg)
+	}
+
+	request := unescapedObj{"query": StringV(fql), "arguments": arguments}
+
+	return client.Query(request, append(configs, func(cfg *queryConfig) { cfg.fql = true })...)
+}
@@ -0,0 +1,61 @@
+package faunadb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFQL(t *testing.T) {
+	server := newMockServer(`{"data": {"name": "Fireball"}, "summary": ""}`)
+	defer server.Close()
+
+	client := NewFaunaClient("secret", Endpoint("http://localhost:1"), FQLEndpoint(server.URL+"/query/1"))
+
+	value, err := client.QueryFQL("Spells.byName(name).first()", map[string]interface{}{
+		"name":  "Fireball",
+		"ref":   RefClass(Class("spells"), "42"),
+		"level": Obj{"min": 1},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"name": StringV("Fireball")}, value)
+	require.Equal(t, "/query/1", server.requests[0].URL.Path)
+	require.Equal(t, "Basic c2VjcmV0:", server.requestHeader(0).Get("Authorization"))
+	require.Equal(t,
+		[]string{`{"arguments":{"level":{"object":{"min":1}},"name":"Fireball",` +
+			`"ref":{"id":"42","ref":{"class":"spells"}}},"query":"Spells.byName(name).first()"}`},
+		server.requestBodies(),
+	)
+}
+
+func TestQueryFQLWithoutArguments(t *testing.T) {
+	server := newMockServer(`{"data": 3}`)
+	defer server.Close()
+
+	value, err := server.client(FQLEndpoint(server.URL)).QueryFQL("1 + 2", nil, Tags(map[string]string{"app": "test"}))
+
+	require.NoError(t, err)
+	require.Equal(t, LongV(3), value)
+	require.Equal(t, []string{`{"arguments":{},"query":"1 + 2"}`}, server.requestBodies())
+	require.Equal(t, "app=test", server.requestHeader(0).Get("X-Fauna-Tags"))
+}
+
+func TestQueryFQLRequiresEndpoint(t *testing.T) {
+	server := newMockServer(`{"data": null}`)
+	defer server.Close()
+
+	_, err := server.client().QueryFQL("1 + 2", nil)
+
+	require.Equal(t, errFQLEndpointNotConfigured, err)
+	require.Empty(t, server.requestBodies())
+}
+
+func TestQueryFQLReportsMissingData(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	_, err := server.client(FQLEndpoint(server.URL)).QueryFQL("1 + 2", nil)
+
+	require.EqualError(t, err, "Error while extracting path: data. Object key data not found")
+}
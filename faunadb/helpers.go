@@ -0,0 +1,406 @@
+package faunadb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+BatchDecode decodes each value informed, usually the result of a BatchQuery, into the slice pointed by target.
+The slice is resized to the number of values, and elements that fail to decode are left with their zero value.
+
+It returns nil if all values were decoded. Otherwise, the errors returned have one entry per value informed,
+holding the error for the value in the same position, or nil if it was decoded successfully. For example:
+
+	var spells []Spell
+	values, _ := client.BatchQuery(exprs)
+
+	if errs := BatchDecode(values, &spells); errs != nil {
+		// errs[i] is the error of values[i]
+	}
+*/
+func BatchDecode(values []Value, target interface{}) []error {
+	ptr := reflect.ValueOf(target)
+
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		err := fmt.Errorf("Error while batch decoding values: Expected a pointer to a slice but got %T", target)
+		errs := make([]error, len(values))
+
+		for i := range errs {
+			errs[i] = err
+		}
+
+		return errs
+	}
+
+	slice := reflect.MakeSlice(ptr.Elem().Type(), len(values), len(values))
+	errs := make([]error, len(values))
+	failed := false
+
+	for i, value := range values {
+		elem := reflect.New(slice.Type().Elem())
+
+		if value == nil {
+			value = NullV{}
+		}
+
+		if errs[i] = value.Get(elem.Interface()); errs[i] != nil {
+			failed = true
+			continue
+		}
+
+		slice.Index(i).Set(elem.Elem())
+	}
+
+	ptr.Elem().Set(slice)
+
+	if failed {
+		return errs
+	}
+
+	return nil
+}
+
+// AsArrayV returns the value informed if it is an array. The boolean returned is false if it is not.
+func AsArrayV(value Value) (arr ArrayV, ok bool) {
+	arr, ok = value.(ArrayV)
+	return
+}
+
+// AsObjectV returns the value informed if it is an object. The boolean returned is false if it is not.
+func AsObjectV(value Value) (obj ObjectV, ok bool) {
+	obj, ok = value.(ObjectV)
+	return
+}
+
+// ToArrayV returns the value informed if it is an array, otherwise an error describing the value's type.
+func ToArrayV(value Value) (ArrayV, error) {
+	if arr, ok := AsArrayV(value); ok {
+		return arr, nil
+	}
+
+	return nil, fmt.Errorf("Error while converting value: Expected value to be an array but was a %T", value)
+}
+
+// ToObjectV returns the value informed if it is an object, otherwise an error describing the value's type.
+func ToObjectV(value Value) (ObjectV, error) {
+	if obj, ok := AsObjectV(value); ok {
+		return obj, nil
+	}
+
+	return nil, fmt.Errorf("Error while converting value: Expected value to be an object but was a %T", value)
+}
+
+/*
+DiffObjects computes the field level differences between two versions of an object, such as the data of an instance
+before and after a change. It returns the keys only present in the new object as added, the keys present in both
+objects with different values as changed, holding their new values, and the keys only present in the old object as
+removed, holding their old values. Values are compared with ValuesEqual.
+
+Nested objects present in both versions are compared recursively: each result holds only the nested keys that
+differ, keeping the nesting of the original objects. For example:
+
+	old := ObjectV{"name": StringV("Fire"), "stats": ObjectV{"level": LongV(1), "cost": LongV(10)}}
+	new := ObjectV{"name": StringV("Fire"), "stats": ObjectV{"level": LongV(2)}, "tags": ArrayV{}}
+
+	added, changed, removed := DiffObjects(old, new)
+	// added:   ObjectV{"tags": ArrayV{}}
+	// changed: ObjectV{"stats": ObjectV{"level": LongV(2)}}
+	// removed: ObjectV{"stats": ObjectV{"cost": LongV(10)}}
+
+Arrays and other values are not compared recursively: an array with any different element is changed as a whole.
+The results are never nil, and objects without differences produce empty results.
+*/
+func DiffObjects(old, new ObjectV) (added, changed, removed ObjectV) {
+	added, changed, removed = ObjectV{}, ObjectV{}, ObjectV{}
+
+	for key, oldValue := range old {
+		newValue, found := new[key]
+
+		if !found {
+			removed[key] = oldValue
+			continue
+		}
+
+		oldObj, oldIsObj := oldValue.(ObjectV)
+		newObj, newIsObj := newValue.(ObjectV)
+
+		if oldIsObj && newIsObj {
+			nestedAdded, nestedChanged, nestedRemoved := DiffObjects(oldObj, newObj)
+			setIfNotEmpty(added, key, nestedAdded)
+			setIfNotEmpty(changed, key, nestedChanged)
+			setIfNotEmpty(removed, key, nestedRemoved)
+		} else if !ValuesEqual(oldValue, newValue) {
+			changed[key] = newValue
+		}
+	}
+
+	for key, newValue := range new {
+		if _, found := old[key]; !found {
+			added[key] = newValue
+		}
+	}
+
+	return
+}
+
+func setIfNotEmpty(obj ObjectV, key string, value ObjectV) {
+	if len(value) > 0 {
+		obj[key] = value
+	}
+}
+
+// Flatten concatenates the arrays contained in the array informed, removing one level of nesting.
+// For example, [[1, 2], [3, [4]]] is flattened to [1, 2, 3, [4]]. All elements must be arrays.
+func Flatten(value Value) (ArrayV, error) {
+	arr, ok := value.(ArrayV)
+	if !ok {
+		return nil, fmt.Errorf("Error while flattening value: Expected value to be an array but was a %T", value)
+	}
+
+	flat := ArrayV{}
+
+	for i, elem := range arr {
+		nested, ok := elem.(ArrayV)
+		if !ok {
+			return nil, fmt.Errorf("Error while flattening value: Expected element %d to be an array but was a %T", i, elem)
+		}
+
+		flat = append(flat, nested...)
+	}
+
+	return flat, nil
+}
+
+/*
+FlattenObject converts an object into flat key/value pairs, such as the ones of form data. Nested objects and arrays
+are flattened into keys with the path of their scalar values, joined by dots, using indexes for array elements.
+For example:
+
+	FlattenObject(ObjectV{"name": StringV("Fireball"), "stats": ObjectV{"level": LongV(3)}, "tags": ArrayV{StringV("fire")}})
+	// map[string]string{"name": "Fireball", "stats.level": "3", "tags.0": "fire"}
+
+Scalars are converted to strings as follows: strings are kept as they are, numbers and booleans are formatted as by
+strconv, null is an empty string, timestamps and dates are formatted as FaunaDB represents them, bytes are encoded
+in standard base64, and refs are formatted as their legacy path, such as "classes/spells/42". Empty objects and
+arrays produce no keys, and keys containing dots are not escaped.
+
+It returns an error for values without a string representation: set refs, queries, and refs to other databases.
+*/
+func FlattenObject(obj ObjectV) (map[string]string, error) {
+	flat := make(map[string]string)
+
+	if err := flattenValue(flat, "", obj); err != nil {
+		return nil, err
+	}
+
+	return flat, nil
+}
+
+func flattenValue(flat map[string]string, key string, value Value) error {
+	prefix := key
+	if prefix != "" {
+		prefix += "."
+	}
+
+	switch v := value.(type) {
+	case ObjectV:
+		for elemKey, elem := range v {
+			if err := flattenValue(flat, prefix+elemKey, elem); err != nil {
+				return err
+			}
+		}
+	case ArrayV:
+		for i, elem := range v {
+			if err := flattenValue(flat, prefix+strconv.Itoa(i), elem); err != nil {
+				return err
+			}
+		}
+	default:
+		str, ok := flatString(value)
+		if !ok {
+			return fmt.Errorf("Error while flattening object: Can not flatten value of type %T at %q", value, key)
+		}
+
+		flat[key] = str
+	}
+
+	return nil
+}
+
+func flatString(value Value) (string, bool) {
+	switch v := value.(type) {
+	case nil, NullV:
+		return "", true
+	case StringV:
+		return string(v), true
+	case LongV:
+		return strconv.FormatInt(int64(v), 10), true
+	case DoubleV:
+		return strconv.FormatFloat(float64(v), 'g', -1, 64), true
+	case BooleanV:
+		return strconv.FormatBool(bool(v)), true
+	case TimeV:
+		return time.Time(v).UTC().Format(timeFormat), true
+	case DateV:
+		return time.Time(v).Format(dateFormat), true
+	case BytesV:
+		return base64.StdEncoding.EncodeToString(v), true
+	case RefV:
+		return legacyRefPath(&v)
+	}
+
+	return "", false
+}
+
+// legacyRefPath formats a ref as a legacy path, the inverse of structuredRefV. Refs to other databases have no path.
+func legacyRefPath(ref *RefV) (string, bool) {
+	if ref.Database != nil {
+		return "", false
+	}
+
+	if ref.Collection == nil {
+		return ref.ID, true
+	}
+
+	collection, ok := legacyRefPath(ref.Collection)
+	return collection + "/" + ref.ID, ok
+}
+
+/*
+ValuesEqual structurally compares two FaunaDB values. Objects and arrays are compared recursively. Dates are
+compared by calendar date, as DateV.Equal does. Timestamps are compared by the instant they represent, regardless
+of their location. Refs are compared as RefV.Equal does, regardless of their legacy or structured representation.
+NullV is equal to a nil Value.
+
+Numbers are only equal when they are of the same type: LongV(1) is not equal to DoubleV(1).
+Use NumericValuesEqual to compare numbers by their numeric value instead.
+*/
+func ValuesEqual(a, b Value) bool { return valuesEqual(a, b, false) }
+
+// NumericValuesEqual works like ValuesEqual but compares numbers by their numeric value,
+// so that LongV(1) is equal to DoubleV(1).
+func NumericValuesEqual(a, b Value) bool { return valuesEqual(a, b, true) }
+
+func valuesEqual(a, b Value, numeric bool) bool {
+	if a == nil {
+		a = NullV{}
+	}
+
+	if b == nil {
+		b = NullV{}
+	}
+
+	switch x := a.(type) {
+	case LongV:
+		switch y := b.(type) {
+		case LongV:
+			return x == y
+		case DoubleV:
+			return numeric && DoubleV(x) == y
+		}
+	case DoubleV:
+		switch y := b.(type) {
+		case DoubleV:
+			return x == y
+		case LongV:
+			return numeric && x == DoubleV(y)
+		}
+	case DateV:
+		if y, ok := b.(DateV); ok {
+			return x.Equal(y)
+		}
+	case TimeV:
+		if y, ok := b.(TimeV); ok {
+			return time.Time(x).Equal(time.Time(y))
+		}
+	case RefV:
+		if y, ok := b.(RefV); ok {
+			return refsEqual(&x, &y)
+		}
+	case BytesV:
+		if y, ok := b.(BytesV); ok {
+			return bytes.Equal(x, y)
+		}
+	case QueryV:
+		if y, ok := b.(QueryV); ok {
+			return bytes.Equal(x.lambda, y.lambda)
+		}
+	case SetRefV:
+		if y, ok := b.(SetRefV); ok {
+			return objectsEqual(x.Parameters, y.Parameters, numeric)
+		}
+	case ObjectV:
+		if y, ok := b.(ObjectV); ok {
+			return objectsEqual(x, y, numeric)
+		}
+	case ArrayV:
+		if y, ok := b.(ArrayV); ok {
+			return arraysEqual(x, y, numeric)
+		}
+	default:
+		return a == b
+	}
+
+	return false
+}
+
+func refsEqual(a, b *RefV) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	a, b = structuredRefV(a), structuredRefV(b)
+
+	return a.ID == b.ID && refsEqual(a.Collection, b.Collection) && refsEqual(a.Database, b.Database)
+}
+
+// structuredRefV converts legacy refs, such as "classes/spells/42", to their structured form. The parent path of
+// the ID becomes the collection of the ref. Structured refs are returned as they are.
+func structuredRefV(ref *RefV) *RefV {
+	if ref.Collection != nil || ref.Database != nil {
+		return ref
+	}
+
+	slash := strings.LastIndex(ref.ID, "/")
+	if slash < 0 {
+		return ref
+	}
+
+	return &RefV{ID: ref.ID[slash+1:], Collection: &RefV{ID: ref.ID[:slash]}}
+}
+
+func objectsEqual(a, b map[string]Value, numeric bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		other, found := b[key]
+
+		if !found || !valuesEqual(value, other, numeric) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func arraysEqual(a, b []Value, numeric bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !valuesEqual(a[i], b[i], numeric) {
+			return false
+		}
+	}
+
+	return true
+}
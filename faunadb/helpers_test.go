@@ -0,0 +1,341 @@
+package faunadb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuesEqual(t *testing.T) {
+	instant := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+	elsewhere := instant.In(time.FixedZone("UTC+3", 3*60*60))
+
+	tonga, samoa := time.FixedZone("+13", 13*60*60), time.FixedZone("-11", -11*60*60)
+	midnightInTonga := time.Date(2017, time.January, 1, 0, 0, 0, 0, tonga)
+	midnightInSamoa := time.Date(2017, time.January, 1, 0, 0, 0, 0, samoa)
+	noon := time.Date(2017, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		a, b     Value
+		expected bool
+	}{
+		{StringV("a"), StringV("a"), true},
+		{StringV("a"), StringV("b"), false},
+		{LongV(1), LongV(1), true},
+		{LongV(1), LongV(2), false},
+		{LongV(1), DoubleV(1), false},
+		{DoubleV(1.5), DoubleV(1.5), true},
+		{BooleanV(true), BooleanV(true), true},
+		{BooleanV(true), StringV("true"), false},
+		{NullV{}, NullV{}, true},
+		{NullV{}, nil, true},
+		{NullV{}, LongV(0), false},
+		{TimeV(instant), TimeV(elsewhere), true},
+		{TimeV(instant), TimeV(instant.Add(time.Nanosecond)), false},
+		{DateV(instant), DateV(elsewhere), true},
+		{DateV(midnightInTonga), DateV(midnightInSamoa), true},
+		{DateV(noon.In(samoa)), DateV(noon.In(tonga)), false},
+		{DateV(instant), TimeV(instant), false},
+		{RefV{ID: "classes/spells/1"}, RefV{ID: "classes/spells/1"}, true},
+		{RefV{ID: "classes/spells/1"}, RefV{ID: "classes/spells/2"}, false},
+		{RefV{ID: "1", Collection: &RefV{ID: "spells"}}, RefV{ID: "1", Collection: &RefV{ID: "spells"}}, true},
+		{RefV{ID: "1", Collection: &RefV{ID: "spells"}}, RefV{ID: "1", Collection: &RefV{ID: "books"}}, false},
+		{RefV{ID: "1", Collection: &RefV{ID: "spells"}}, RefV{ID: "1"}, false},
+		{RefV{ID: "spells/1"}, RefV{ID: "1", Collection: &RefV{ID: "spells"}}, true},
+		{RefV{ID: "spells/1"}, RefV{ID: "1", Collection: &RefV{ID: "spells"}, Database: &RefV{ID: "db"}}, false},
+		{BytesV{1, 2}, BytesV{1, 2}, true},
+		{BytesV{1, 2}, BytesV{2, 1}, false},
+		{QueryV{json.RawMessage(`{"lambda":"x"}`)}, QueryV{json.RawMessage(`{"lambda":"x"}`)}, true},
+		{SetRefV{ObjectV{"match": RefV{ID: "indexes/all"}}}, SetRefV{ObjectV{"match": RefV{ID: "indexes/all"}}}, true},
+		{ArrayV{LongV(1), StringV("a")}, ArrayV{LongV(1), StringV("a")}, true},
+		{ArrayV{LongV(1), StringV("a")}, ArrayV{StringV("a"), LongV(1)}, false},
+		{ArrayV{LongV(1)}, ArrayV{LongV(1), LongV(1)}, false},
+		{ObjectV{"a": ObjectV{"b": TimeV(instant)}}, ObjectV{"a": ObjectV{"b": TimeV(elsewhere)}}, true},
+		{ObjectV{"a": LongV(1)}, ObjectV{"b": LongV(1)}, false},
+		{ObjectV{"a": LongV(1)}, ObjectV{"a": LongV(1), "b": LongV(2)}, false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, ValuesEqual(test.a, test.b), "%#v == %#v", test.a, test.b)
+		require.Equal(t, test.expected, ValuesEqual(test.b, test.a), "%#v == %#v", test.b, test.a)
+	}
+}
+
+func TestNumericValuesEqual(t *testing.T) {
+	require.True(t, NumericValuesEqual(LongV(1), DoubleV(1)))
+	require.True(t, NumericValuesEqual(DoubleV(1), LongV(1)))
+	require.False(t, NumericValuesEqual(LongV(1), DoubleV(1.5)))
+	require.True(t, NumericValuesEqual(ArrayV{LongV(1)}, ArrayV{DoubleV(1)}))
+	require.True(t, NumericValuesEqual(ObjectV{"n": DoubleV(2)}, ObjectV{"n": LongV(2)}))
+}
+
+func TestFlatten(t *testing.T) {
+	flat, err := Flatten(ArrayV{
+		ArrayV{LongV(1), LongV(2)},
+		ArrayV{},
+		ArrayV{LongV(3), ArrayV{LongV(4)}},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{LongV(1), LongV(2), LongV(3), ArrayV{LongV(4)}}, flat)
+}
+
+func TestFlattenEmptyArray(t *testing.T) {
+	flat, err := Flatten(ArrayV{})
+
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{}, flat)
+}
+
+func TestFailToFlattenMixedDepthArray(t *testing.T) {
+	_, err := Flatten(ArrayV{ArrayV{LongV(1)}, LongV(2)})
+	require.EqualError(t, err, "Error while flattening value: Expected element 1 to be an array but was a faunadb.LongV")
+}
+
+func TestFailToFlattenNonArray(t *testing.T) {
+	_, err := Flatten(ObjectV{})
+	require.EqualError(t, err, "Error while flattening value: Expected value to be an array but was a faunadb.ObjectV")
+}
+
+func TestBatchDecode(t *testing.T) {
+	type spell struct {
+		Name string `fauna:"name"`
+	}
+
+	var spells []spell
+
+	errs := BatchDecode([]Value{
+		ObjectV{"name": StringV("Fire")},
+		ObjectV{"name": StringV("Water")},
+	}, &spells)
+
+	require.Nil(t, errs)
+	require.Equal(t, []spell{{"Fire"}, {"Water"}}, spells)
+}
+
+func TestBatchDecodeReportsErrorsPerValue(t *testing.T) {
+	var numbers []int
+
+	errs := BatchDecode([]Value{LongV(1), StringV("two"), LongV(3)}, &numbers)
+
+	require.Len(t, errs, 3)
+	require.NoError(t, errs[0])
+	require.EqualError(t, errs[1], "Error while decoding fauna value at: <root>. Can not assign value of type \"faunadb.StringV\" to a value of type \"int\"")
+	require.NoError(t, errs[2])
+	require.Equal(t, []int{1, 0, 3}, numbers)
+}
+
+func TestBatchDecodeRequiresPointerToSlice(t *testing.T) {
+	var number int
+
+	errs := BatchDecode([]Value{LongV(1)}, &number)
+
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "Error while batch decoding values: Expected a pointer to a slice but got *int")
+}
+
+func TestConvertValuesToArrayAndObject(t *testing.T) {
+	arr := ArrayV{LongV(1), StringV("two")}
+	obj := ObjectV{"one": LongV(1)}
+
+	nonCollections := []Value{
+		StringV("str"),
+		LongV(1),
+		DoubleV(1.5),
+		BooleanV(true),
+		DateV(time.Unix(0, 0).UTC()),
+		TimeV(time.Unix(0, 0).UTC()),
+		RefV{ID: "classes/spells/42"},
+		SetRefV{ObjectV{"match": RefV{ID: "indexes/all_spells"}}},
+		NullV{},
+		BytesV{1, 2},
+		QueryV{json.RawMessage(`{"lambda":"x","expr":{"var":"x"}}`)},
+		nil,
+	}
+
+	asArray, ok := AsArrayV(arr)
+	require.True(t, ok)
+	require.Equal(t, arr, asArray)
+
+	asObject, ok := AsObjectV(obj)
+	require.True(t, ok)
+	require.Equal(t, obj, asObject)
+
+	_, ok = AsArrayV(obj)
+	require.False(t, ok)
+
+	_, ok = AsObjectV(arr)
+	require.False(t, ok)
+
+	for _, value := range nonCollections {
+		_, ok = AsArrayV(value)
+		require.False(t, ok, "%#v", value)
+
+		_, ok = AsObjectV(value)
+		require.False(t, ok, "%#v", value)
+	}
+}
+
+func TestToArrayVAndToObjectV(t *testing.T) {
+	arr, err := ToArrayV(ArrayV{LongV(1)})
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{LongV(1)}, arr)
+
+	obj, err := ToObjectV(ObjectV{"one": LongV(1)})
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"one": LongV(1)}, obj)
+
+	_, err = ToArrayV(ObjectV{})
+	require.EqualError(t, err, "Error while converting value: Expected value to be an array but was a faunadb.ObjectV")
+
+	_, err = ToObjectV(StringV("str"))
+	require.EqualError(t, err, "Error while converting value: Expected value to be an object but was a faunadb.StringV")
+
+	_, err = ToObjectV(nil)
+	require.EqualError(t, err, "Error while converting value: Expected value to be an object but was a <nil>")
+}
+
+func TestDiffObjects(t *testing.T) {
+	old := ObjectV{
+		"name":    StringV("Fireball"),
+		"cost":    LongV(10),
+		"removed": BooleanV(true),
+		"tags":    ArrayV{StringV("fire")},
+		"stats": ObjectV{
+			"level":     LongV(1),
+			"unchanged": StringV("same"),
+			"cooldown":  LongV(5),
+			"nested":    ObjectV{"deep": LongV(1)},
+		},
+	}
+
+	new := ObjectV{
+		"name":  StringV("Fireball"),
+		"cost":  LongV(12),
+		"added": StringV("new"),
+		"tags":  ArrayV{StringV("fire"), StringV("aoe")},
+		"stats": ObjectV{
+			"level":     LongV(2),
+			"unchanged": StringV("same"),
+			"range":     LongV(30),
+			"nested":    ObjectV{"deep": LongV(1)},
+		},
+	}
+
+	added, changed, removed := DiffObjects(old, new)
+
+	require.Equal(t, ObjectV{"added": StringV("new"), "stats": ObjectV{"range": LongV(30)}}, added)
+	require.Equal(t,
+		ObjectV{
+			"cost":  LongV(12),
+			"tags":  ArrayV{StringV("fire"), StringV("aoe")},
+			"stats": ObjectV{"level": LongV(2)},
+		},
+		changed,
+	)
+	require.Equal(t, ObjectV{"removed": BooleanV(true), "stats": ObjectV{"cooldown": LongV(5)}}, removed)
+}
+
+func TestDiffObjectsWithChangedTypes(t *testing.T) {
+	added, changed, removed := DiffObjects(
+		ObjectV{"stats": ObjectV{"level": LongV(1)}, "cost": LongV(1)},
+		ObjectV{"stats": StringV("none"), "cost": DoubleV(1)},
+	)
+
+	require.Empty(t, added)
+	require.Equal(t, ObjectV{"stats": StringV("none"), "cost": DoubleV(1)}, changed)
+	require.Empty(t, removed)
+}
+
+func TestDiffEqualObjects(t *testing.T) {
+	obj := ObjectV{"ref": RefV{ID: "classes/spells/42"}, "data": ObjectV{"name": StringV("Fireball")}}
+
+	added, changed, removed := DiffObjects(obj, obj)
+
+	require.Equal(t, ObjectV{}, added)
+	require.Equal(t, ObjectV{}, changed)
+	require.Equal(t, ObjectV{}, removed)
+}
+
+func TestRefsEqual(t *testing.T) {
+	structured := RefV{ID: "42", Collection: &RefV{ID: "spells", Collection: &RefV{ID: "collections"}}}
+
+	parsed, err := ParseRef(`Ref(Collection("spells"), "42")`)
+	require.NoError(t, err)
+
+	var decoded RefV
+	require.NoError(t, decodeJSON(
+		`{"@ref": {"id": "42", "collection": {"@ref": {"id": "spells", "collection": {"@ref": {"id": "collections"}}}}}}`,
+		&decoded,
+	))
+
+	require.True(t, structured.Equal(parsed))
+	require.True(t, structured.Equal(decoded))
+	require.True(t, structured.Equal(RefV{ID: "collections/spells/42"}))
+	require.True(t, RefV{ID: "collections/spells/42"}.Equal(decoded))
+	require.True(t, ValuesEqual(ArrayV{structured}, ArrayV{RefV{ID: "collections/spells/42"}}))
+
+	require.False(t, structured.Equal(RefV{ID: "classes/spells/42"}))
+	require.False(t, structured.Equal(RefV{ID: "collections/spells/43"}))
+	require.False(t, structured.Equal(RefV{ID: "42"}))
+}
+
+func TestFlattenObject(t *testing.T) {
+	obj := ObjectV{
+		"name":    StringV("Fireball"),
+		"level":   LongV(3),
+		"cost":    DoubleV(2.5),
+		"active":  BooleanV(true),
+		"expires": NullV{},
+		"ref":     RefV{ID: "42", Collection: &RefV{ID: "spells", Collection: &RefV{ID: "classes"}}},
+		"created": TimeV(time.Date(2017, time.January, 1, 10, 0, 0, 500, time.UTC)),
+		"day":     DateV(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		"key":     BytesV{0x1, 0x2},
+		"stats": ObjectV{
+			"damage": ObjectV{"min": LongV(1), "max": LongV(10)},
+			"range":  LongV(30),
+		},
+		"tags":   ArrayV{StringV("fire"), ArrayV{StringV("aoe")}, ObjectV{"id": LongV(7)}},
+		"empty":  ObjectV{},
+		"nested": ArrayV{},
+	}
+
+	flat, err := FlattenObject(obj)
+	require.NoError(t, err)
+	require.Equal(t,
+		map[string]string{
+			"name":             "Fireball",
+			"level":            "3",
+			"cost":             "2.5",
+			"active":           "true",
+			"expires":          "",
+			"ref":              "classes/spells/42",
+			"created":          "2017-01-01T10:00:00.0000005Z",
+			"day":              "2017-01-01",
+			"key":              "AQI=",
+			"stats.damage.min": "1",
+			"stats.damage.max": "10",
+			"stats.range":      "30",
+			"tags.0":           "fire",
+			"tags.1.0":         "aoe",
+			"tags.2.id":        "7",
+		},
+		flat,
+	)
+}
+
+func TestFlattenEmptyObject(t *testing.T) {
+	flat, err := FlattenObject(ObjectV{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{}, flat)
+}
+
+func TestFailToFlattenValuesWithoutStrings(t *testing.T) {
+	_, err := FlattenObject(ObjectV{"data": ObjectV{"set": SetRefV{ObjectV{"match": RefV{ID: "indexes/all"}}}}})
+	require.EqualError(t, err, `Error while flattening object: Can not flatten value of type faunadb.SetRefV at "data.set"`)
+
+	_, err = FlattenObject(ObjectV{"ref": RefV{ID: "spells", Collection: &RefV{ID: "classes"}, Database: &RefV{ID: "db"}}})
+	require.EqualError(t, err, `Error while flattening object: Can not flatten value of type faunadb.RefV at "ref"`)
+}
@@ -0,0 +1,116 @@
+package faunadb
+
+/*
+CreateIndexConfig describes the parameters of an index to be created with CreateIndex. Zero valued fields are not
+encoded, leaving them to FaunaDB defaults. For example:
+
+	CreateIndex(CreateIndexConfig{
+		Name: "spells_by_element",
+		Source: IndexSource{
+			Class:  Class("spells"),
+			Fields: map[string]interface{}{"element": Query(Lambda("spell", Casefold(Select(Arr{"data", "element"}, Var("spell")))))},
+		},
+		Terms:  []IndexTerm{{Binding: "element"}},
+		Values: []IndexValue{{Field: []string{"data", "cost"}, Reverse: true}, {Field: []string{"ref"}}},
+	})
+
+See: https://fauna.com/documentation/objects#indexes
+*/
+type CreateIndexConfig struct {
+	Name       string
+	Source     interface{} // A class ref or an IndexSource
+	Terms      []IndexTerm
+	Values     []IndexValue
+	Unique     bool
+	Serialized bool
+	Partitions int
+	Data       interface{}
+}
+
+// IndexSource describes the source of an index with bindings: the class indexed and the bindings computed for each
+// of its instances, mapping binding names to Query expressions.
+type IndexSource struct {
+	Class  interface{}
+	Fields map[string]interface{}
+}
+
+// IndexTerm describes a term of an index, matched by the Match function. A term is either the path of a field of
+// the instances indexed, such as []string{"data", "name"}, or the name of a binding of the index source.
+type IndexTerm struct {
+	Field   []string
+	Binding string
+}
+
+// IndexValue describes a value covered by an index, returned when paginating its matches. A value is either the
+// path of a field of the instances indexed or the name of a binding of the index source. Reverse sorts the index
+// by the value in descending order.
+type IndexValue struct {
+	Field   []string
+	Reverse bool
+	Binding string
+}
+
+func (config CreateIndexConfig) expr() {}
+func (source IndexSource) expr()       {}
+func (term IndexTerm) expr()           {}
+func (value IndexValue) expr()         {}
+
+// MarshalJSON implements json.Marshaler by encoding the configuration as the parameters of CreateIndex.
+func (config CreateIndexConfig) MarshalJSON() ([]byte, error) {
+	obj := Obj{"name": config.Name, "source": config.Source}
+
+	if len(config.Terms) > 0 {
+		obj["terms"] = config.Terms
+	}
+
+	if len(config.Values) > 0 {
+		obj["values"] = config.Values
+	}
+
+	if config.Unique {
+		obj["unique"] = true
+	}
+
+	if config.Serialized {
+		obj["serialized"] = true
+	}
+
+	if config.Partitions > 0 {
+		obj["partitions"] = config.Partitions
+	}
+
+	if config.Data != nil {
+		obj["data"] = config.Data
+	}
+
+	return marshalJSON(obj)
+}
+
+// MarshalJSON implements json.Marshaler by encoding the source as an index source object.
+func (source IndexSource) MarshalJSON() ([]byte, error) {
+	return marshalJSON(Obj{"class": source.Class, "fields": source.Fields})
+}
+
+// MarshalJSON implements json.Marshaler by encoding the term as an index term object.
+func (term IndexTerm) MarshalJSON() ([]byte, error) {
+	return marshalJSON(indexField(term.Field, term.Binding))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the value as an index value object.
+func (value IndexValue) MarshalJSON() ([]byte, error) {
+	obj := indexField(value.Field, value.Binding)
+
+	if value.Reverse {
+		obj["reverse"] = true
+	}
+
+	return marshalJSON(obj)
+}
+
+func indexField(field []string, binding string) Obj {
+	if binding != "" {
+		return Obj{"binding": binding}
+	}
+
+	return Obj{"field": field}
+}
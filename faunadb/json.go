@@ -5,10 +5,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"strings"
 	"time"
 )
 
+// ParseValue decodes a FaunaDB value from its JSON representation, such as a previously cached query response.
+func ParseValue(reader io.Reader) (Value, error) { return parseJSON(reader) }
+
+// ParseResponse decodes a FaunaDB query response body, unwrapping the value from its "resource" envelope
+// exactly like FaunaClient.Query does.
+func ParseResponse(reader io.Reader) (Value, error) { return parseEnvelope(reader, resource) }
+
+func parseEnvelope(reader io.Reader, envelope Field) (Value, error) {
+	value, err := parseJSON(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value.At(envelope).GetValue()
+}
+
 func parseJSON(reader io.Reader) (Value, error) {
 	decoder := json.NewDecoder(reader)
 	decoder.UseNumber()
@@ -17,6 +35,17 @@ func parseJSON(reader io.Reader) (Value, error) {
 	return parser.parseNext()
 }
 
+var (
+	dateLayouts = []string{"2006-01-02"}
+
+	// FaunaDB returns times in UTC with a Z suffix, but offsets and times without a zone, assumed as UTC, are accepted.
+	timeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.999999999Z0700",
+		"2006-01-02T15:04:05.999999999",
+	}
+)
+
 type wrongToken struct {
 	expected string
 	got      json.Token
@@ -72,21 +101,19 @@ func (p *jsonParser) parseSpecialObject() (value Value, err error) {
 	var firstKey string
 
 	if firstKey, err = p.readString(); err == nil {
-		switch firstKey {
-		case "@ref":
-			value, err = p.parseRef()
-		case "@set":
-			value, err = p.parseSet()
-		case "@date":
-			value, err = p.parseDate("2006-01-02", func(t time.Time) Value { return DateV(t) })
-		case "@ts":
-			value, err = p.parseDate("2006-01-02T15:04:05.999999999Z", func(t time.Time) Value { return TimeV(t) })
-		case "@obj":
-			value, err = p.readSingleObject()
-		case "@bytes":
-			value, err = p.parseBytes()
-		case "@query":
+		switch {
+		case firstKey == "@query":
 			value, err = p.parseQuery()
+		case firstKey == "@obj":
+			value, err = p.parseEscapedObject()
+		case isSpecialKey(firstKey):
+			var inner Value
+
+			if inner, err = p.parseNext(); err == nil {
+				if err = p.ensureNoMoreTokens(); err == nil {
+					value, err = resolveSpecial(firstKey, inner)
+				}
+			}
 		default:
 			value, err = p.parseObject(firstKey)
 		}
@@ -95,44 +122,129 @@ func (p *jsonParser) parseSpecialObject() (value Value, err error) {
 	return
 }
 
-func (p *jsonParser) parseRef() (value Value, err error) {
-	var id string
+// isSpecialKey reports whether the key informed marks an object as the representation of a special FaunaDB type,
+// such as {"@ref": "classes/spells/42"}.
+func isSpecialKey(key string) bool {
+	switch key {
+	case "@ref", "@set", "@date", "@ts", "@obj", "@bytes", "@query":
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveSpecial converts the value informed, parsed from a special object with the key informed, into the special
+// type the key represents. @query and @obj values are resolved by their parsers: queries keep the raw JSON of their
+// lambda, while escaped objects are read without resolving special keys, so objects can hold keys starting with @.
+// It is shared by the response parser and the conversion of values decoded by JSONHooks.
+func resolveSpecial(key string, value Value) (Value, error) {
+	switch key {
+	case "@ref":
+		switch v := value.(type) {
+		case StringV:
+			return RefV{ID: string(v)}, nil
+		case ObjectV:
+			ref, err := structuredRef(v)
+			if err != nil {
+				return nil, err
+			}
+
+			return ref, nil
+		default:
+			return nil, wrongToken{"a string or an object", value}
+		}
+	case "@set":
+		if obj, ok := value.(ObjectV); ok {
+			return SetRefV{obj}, nil
+		}
+	case "@date":
+		return parseStrTime(value, dateLayouts, func(t time.Time) Value { return DateV(t) })
+	case "@ts":
+		return parseStrTime(value, timeLayouts, func(t time.Time) Value { return TimeV(t) })
+	case "@bytes":
+		encoded, ok := value.(StringV)
+		if !ok {
+			return nil, wrongToken{"a string", value}
+		}
 
-	if id, err = p.readSingleString(); err == nil {
-		value = RefV{id}
+		bytes, err := base64.StdEncoding.DecodeString(string(encoded))
+		if err != nil {
+			return nil, err
+		}
+
+		return BytesV(bytes), nil
 	}
 
-	return
+	return nil, wrongToken{"a single object", value}
 }
 
-func (p *jsonParser) parseSet() (value Value, err error) {
-	var obj ObjectV
+func structuredRef(value Value) (ref RefV, err error) {
+	obj, ok := value.(ObjectV)
+	if !ok {
+		err = wrongToken{"a ref object", value}
+		return
+	}
+
+	if id, ok := obj["id"].(StringV); ok {
+		ref.ID = string(id)
+	} else {
+		err = wrongToken{"a ref id", obj["id"]}
+		return
+	}
 
-	if obj, err = p.readSingleObject(); err == nil {
-		value = SetRefV{obj}
+	if ref.Collection, err = optionalRef(obj, "collection"); err == nil {
+		ref.Database, err = optionalRef(obj, "database")
 	}
 
 	return
 }
 
-func (p *jsonParser) parseBytes() (value Value, err error) {
-	var encoded string
+func optionalRef(obj ObjectV, key string) (*RefV, error) {
+	value, found := obj[key]
+	if !found {
+		return nil, nil
+	}
 
-	if encoded, err = p.readSingleString(); err == nil {
-		bytes, err := base64.StdEncoding.DecodeString(encoded)
-		if err == nil {
-			value = BytesV(bytes)
-		}
+	if ref, ok := value.(RefV); ok {
+		return &ref, nil
 	}
 
-	return
+	return nil, wrongToken{"a ref", value}
 }
 
 func (p *jsonParser) parseQuery() (value Value, err error) {
 	var lambda json.RawMessage
 
 	if err = p.decoder.Decode(&lambda); err == nil {
-		value = QueryV{lambda}
+		if err = p.ensureNoMoreTokens(); err == nil {
+			value = QueryV{lambda}
+		}
+	}
+
+	return
+}
+
+func (p *jsonParser) parseEscapedObject() (value Value, err error) {
+	var token json.Token
+
+	if token, err = p.decoder.Token(); err != nil {
+		return
+	}
+
+	if token != json.Delim('{') {
+		return nil, wrongToken{"a single object", token}
+	}
+
+	var firstKey string
+
+	if p.hasMore() {
+		if firstKey, err = p.readString(); err != nil {
+			return
+		}
+	}
+
+	if value, err = p.parseObject(firstKey); err == nil {
+		err = p.ensureNoMoreTokens()
 	}
 
 	return
@@ -180,21 +292,24 @@ func (p *jsonParser) parseArray() (Value, error) {
 	return ArrayV(array), nil
 }
 
-func (p *jsonParser) parseDate(format string, fn func(t time.Time) Value) (value Value, err error) {
-	var str string
-
-	if str, err = p.readSingleString(); err == nil {
-		value, err = p.parseStrTime(str, format, fn)
+// parseStrTime parses the time string informed with the first of the layouts that matches it, converting it to UTC.
+// If no layout matches, it returns the error of the first layout.
+func parseStrTime(value Value, layouts []string, fn func(time.Time) Value) (parsed Value, err error) {
+	raw, ok := value.(StringV)
+	if !ok {
+		return nil, wrongToken{"a string", value}
 	}
 
-	return
-}
+	for i, layout := range layouts {
+		t, parseErr := time.Parse(layout, string(raw))
 
-func (p *jsonParser) parseStrTime(raw string, format string, fn func(time.Time) Value) (value Value, err error) {
-	var t time.Time
+		if parseErr == nil {
+			return fn(t.UTC()), nil
+		}
 
-	if t, err = time.Parse(format, raw); err == nil {
-		value = fn(t)
+		if i == 0 {
+			err = parseErr
+		}
 	}
 
 	return
@@ -208,6 +323,10 @@ func (p *jsonParser) parseJSONNumber(number json.Number) (Value, error) {
 		if n, err = number.Float64(); err == nil {
 			return DoubleV(n), nil
 		}
+
+		if math.IsInf(n, 0) {
+			err = fmt.Errorf("Error while parsing number %s: Double value is not finite", number)
+		}
 	} else {
 		var n int64
 		if n, err = number.Int64(); err == nil {
@@ -218,29 +337,6 @@ func (p *jsonParser) parseJSONNumber(number json.Number) (Value, error) {
 	return nil, err
 }
 
-func (p *jsonParser) readSingleString() (str string, err error) {
-	if str, err = p.readString(); err == nil {
-		err = p.ensureNoMoreTokens()
-	}
-
-	return
-}
-
-func (p *jsonParser) readSingleObject() (obj ObjectV, err error) {
-	var value Value
-	var ok bool
-
-	if value, err = p.parseNext(); err == nil {
-		if err = p.ensureNoMoreTokens(); err == nil {
-			if obj, ok = value.(ObjectV); !ok {
-				err = wrongToken{"a single object", value}
-			}
-		}
-	}
-
-	return
-}
-
 func (p *jsonParser) readString() (str string, err error) {
 	var token json.Token
 	var ok bool
@@ -0,0 +1,176 @@
+package faunadb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+)
+
+/*
+JSONHooks configures the FaunaClient structure to encode request bodies with the marshal function informed and to
+decode response bodies with the unmarshal function informed, instead of encoding/json, so that an alternative JSON
+library can be used. Both functions follow the signatures of json.Marshal and json.Unmarshal; a nil function keeps
+the default for its direction. For example:
+
+	client := NewFaunaClient(secret, JSONHooks(jsoniter.Marshal, jsoniter.Unmarshal))
+
+The marshal function receives the whole request expression. Expressions implement json.Marshaler, so the library
+must honor it for nested values. The unmarshal function receives the response body and a pointer to an empty
+interface, which must be filled with the generic types produced by json.Unmarshal. Numbers should be preserved as
+json.Number, like json.Decoder.UseNumber does, so integers are not decoded with the precision of a float64.
+
+Error responses and streams are still decoded with encoding/json.
+*/
+func JSONHooks(marshal func(interface{}) ([]byte, error), unmarshal func([]byte, interface{}) error) ClientConfig {
+	return func(cli *FaunaClient) {
+		cli.marshalHook = marshal
+		cli.unmarshalHook = unmarshal
+	}
+}
+
+func (client *FaunaClient) marshalRequest(expr Expr) ([]byte, error) {
+	if client.marshalHook != nil {
+		return client.marshalHook(expr)
+	}
+
+	return marshalJSON(expr)
+}
+
+// unmarshalEnvelope decodes the body with the client's unmarshal hook, unwrapping the value from its envelope. Empty
+// bodies are reported as io.EOF, as parseEnvelope does.
+func (client *FaunaClient) unmarshalEnvelope(body io.Reader, envelope Field) (value Value, err error) {
+	var data []byte
+	var generic interface{}
+
+	if data, err = ioutil.ReadAll(body); err != nil {
+		return
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, io.EOF
+	}
+
+	if err = client.unmarshalHook(data, &generic); err != nil {
+		return nil, invalidJSONError{err}
+	}
+
+	if value, err = valueFromGeneric(generic); err == nil {
+		value, err = value.At(envelope).GetValue()
+	}
+
+	return
+}
+
+// invalidJSONError marks errors of unmarshal hooks, which are not *json.SyntaxError values, as invalid bodies.
+type invalidJSONError struct{ cause error }
+
+func (err invalidJSONError) Error() string { return err.cause.Error() }
+
+// valueFromGeneric converts a value decoded into the generic types of json.Unmarshal into a FaunaDB value,
+// resolving special objects such as @ref and @ts as the response parser does.
+func valueFromGeneric(generic interface{}) (value Value, err error) {
+	switch v := generic.(type) {
+	case nil:
+		value = NullV{}
+	case bool:
+		value = BooleanV(v)
+	case string:
+		value = StringV(v)
+	case json.Number:
+		var p jsonParser
+		value, err = p.parseJSONNumber(v)
+	case float64:
+		value = DoubleV(v)
+	case int64:
+		value = LongV(v)
+	case int:
+		value = LongV(v)
+	case []interface{}:
+		arr := make(ArrayV, len(v))
+
+		for i, elem := range v {
+			if arr[i], err = valueFromGeneric(elem); err != nil {
+				return nil, err
+			}
+		}
+
+		value = arr
+	case map[string]interface{}:
+		value, err = specialFromGeneric(v)
+	default:
+		err = wrongToken{"a JSON value", v}
+	}
+
+	return
+}
+
+// specialFromGeneric converts an object into a FaunaDB value, resolving special objects with resolveSpecial as the
+// response parser does. Since the order of keys is lost, special keys along with other keys are always rejected.
+func specialFromGeneric(obj map[string]interface{}) (Value, error) {
+	for key, raw := range obj {
+		if !isSpecialKey(key) {
+			continue
+		}
+
+		if len(obj) > 1 {
+			return nil, wrongToken{"end of array or object", otherKey(obj, key)}
+		}
+
+		if key == "@query" {
+			lambda, err := json.Marshal(raw)
+			if err != nil {
+				return nil, err
+			}
+
+			return QueryV{lambda}, nil
+		}
+
+		if key == "@obj" {
+			escaped, ok := raw.(map[string]interface{})
+			if !ok {
+				return nil, wrongToken{"a single object", raw}
+			}
+
+			return objectFromGeneric(escaped)
+		}
+
+		value, err := valueFromGeneric(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolveSpecial(key, value)
+	}
+
+	return objectFromGeneric(obj)
+}
+
+// objectFromGeneric converts an object into an ObjectV without resolving special keys.
+func objectFromGeneric(obj map[string]interface{}) (Value, error) {
+	object := make(ObjectV, len(obj))
+
+	for key, raw := range obj {
+		value, err := valueFromGeneric(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		object[key] = value
+	}
+
+	return object, nil
+}
+
+// otherKey returns the first key of the object, in sorted order, that is not the key informed.
+func otherKey(obj map[string]interface{}, key string) string {
+	var other string
+
+	for k := range obj {
+		if k != key && (other == "" || k < other) {
+			other = k
+		}
+	}
+
+	return other
+}
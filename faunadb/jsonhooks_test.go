@@ -0,0 +1,172 @@
+package faunadb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingJSON struct {
+	marshaled   []interface{}
+	unmarshaled [][]byte
+}
+
+func (r *recordingJSON) marshal(v interface{}) ([]byte, error) {
+	r.marshaled = append(r.marshaled, v)
+	return json.Marshal(v)
+}
+
+func (r *recordingJSON) unmarshal(data []byte, v interface{}) error {
+	r.unmarshaled = append(r.unmarshaled, data)
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
+
+func TestQueryWithJSONHooks(t *testing.T) {
+	response := `{"resource": {
+		"ref": {"@ref": {"id": "42", "collection": {"@ref": {"id": "spells", "collection": {"@ref": {"id": "collections"}}}}}},
+		"ts": 1509244539203043,
+		"data": {
+			"name": "Fireball",
+			"cost": 10.5,
+			"cast": {"@ts": "2017-10-29T02:35:39.203043Z"},
+			"learned": {"@date": "2017-10-29"},
+			"rune": {"@bytes": "AQID"},
+			"tags": ["fire", null, true],
+			"meta": {"@obj": {"@level": 3}},
+			"set": {"@set": {"match": {"@ref": "indexes/spells"}}}
+		}
+	}}`
+
+	server := newMockServer(response)
+	defer server.Close()
+
+	hooks := &recordingJSON{}
+	client := server.client(JSONHooks(hooks.marshal, hooks.unmarshal))
+
+	expr := Get(RefClass(Class("spells"), "42"))
+	value, err := client.Query(expr)
+	require.NoError(t, err)
+
+	expected, err := ParseResponse(bytes.NewBufferString(response))
+	require.NoError(t, err)
+
+	require.Equal(t, expected, value)
+	require.Equal(t, []interface{}{expr}, hooks.marshaled)
+	require.Len(t, hooks.unmarshaled, 1)
+	require.Equal(t, []string{`{"get":{"id":"42","ref":{"class":"spells"}}}`}, server.requestBodies())
+
+	var cast time.Time
+	require.NoError(t, value.At(ObjKey("data", "cast")).Get(&cast))
+	require.Equal(t, time.Date(2017, time.October, 29, 2, 35, 39, 203043000, time.UTC), cast)
+
+	var ts int64
+	require.NoError(t, value.At(ObjKey("ts")).Get(&ts))
+	require.Equal(t, int64(1509244539203043), ts)
+}
+
+func TestQueryLambdaWithJSONHooks(t *testing.T) {
+	server := newMockServer(`{"resource": {"@query": {"lambda": "x", "expr": {"var": "x"}}}}`)
+	defer server.Close()
+
+	hooks := &recordingJSON{}
+
+	value, err := server.client(JSONHooks(nil, hooks.unmarshal)).Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, QueryV{json.RawMessage(`{"expr":{"var":"x"},"lambda":"x"}`)}, value)
+	require.Len(t, hooks.unmarshaled, 1)
+	require.Equal(t, []string{`null`}, server.requestBodies())
+}
+
+func TestMarshalHookErrorsAreReported(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	marshal := func(interface{}) ([]byte, error) { return nil, errors.New("Unsupported expression") }
+
+	_, err := server.client(JSONHooks(marshal, nil)).Query(NullV{})
+	require.EqualError(t, err, "Unsupported expression")
+	require.Empty(t, server.requestBodies())
+}
+
+func TestReportInvalidResponsesWithJSONHooks(t *testing.T) {
+	server := newMockServer("<html>Under maintenance</html>")
+	defer server.Close()
+
+	hooks := &recordingJSON{}
+
+	_, err := server.client(JSONHooks(nil, hooks.unmarshal)).Query(NullV{})
+	require.IsType(t, InvalidResponseError{}, err)
+	require.Equal(t, 200, err.(InvalidResponseError).Status)
+	require.Contains(t, err.Error(), "Error while parsing response: Invalid JSON body with HTTP status 200.")
+}
+
+func TestReportEmptyResponsesWithJSONHooks(t *testing.T) {
+	server := newMockServer("")
+	defer server.Close()
+
+	hooks := &recordingJSON{}
+
+	_, err := server.client(JSONHooks(nil, hooks.unmarshal)).Query(NullV{})
+	require.Equal(t, EmptyResponseError{Status: 200}, err)
+	require.Empty(t, hooks.unmarshaled)
+}
+
+func TestGenericValuesResolveLikeTheResponseParser(t *testing.T) {
+	fixtures := []string{
+		`null`,
+		`[1, 2.5, "three", true, null]`,
+		`{}`,
+		`{"name": "Fireball", "level": {"min": 1}}`,
+		`{"@ref": "classes/spells/42"}`,
+		`{"@ref": {"id": "42", "collection": {"@ref": {"id": "spells", "collection": {"@ref": {"id": "collections"}}}}}}`,
+		`{"@set": {"match": {"@ref": "indexes/spells"}, "terms": "fire"}}`,
+		`{"@ts": "2017-10-29T02:35:39.203043Z"}`,
+		`{"@ts": "2017-10-29T02:35:39+03:00"}`,
+		`{"@date": "2017-10-29"}`,
+		`{"@bytes": "AQID"}`,
+		`{"@obj": {"@level": 3}}`,
+		`{"@obj": {}}`,
+		`{"@obj": {"@ref": "not a ref", "@ts": {"@obj": {"@date": 1}}}}`,
+		`[{"@obj": {"@ref": 1}}, 2]`,
+		`[{"@query": {"expr":{"var":"x"},"lambda":"x"}}, 1]`,
+		`{"@ref": "classes/spells/42", "other": 1}`,
+		`{"@ref": 1}`,
+		`{"@ref": {"id": 1}}`,
+		`{"@ref": {"id": "42", "collection": "spells"}}`,
+		`{"@set": 1}`,
+		`{"@ts": "yesterday"}`,
+		`{"@date": 1}`,
+		`{"@bytes": "!!"}`,
+		`{"@obj": [1]}`,
+		`{"@query": {"lambda": "x"}, "other": 1}`,
+	}
+
+	for _, fixture := range fixtures {
+		parsed, parseErr := ParseValue(strings.NewReader(fixture))
+
+		var generic interface{}
+
+		decoder := json.NewDecoder(strings.NewReader(fixture))
+		decoder.UseNumber()
+		require.NoError(t, decoder.Decode(&generic), fixture)
+
+		converted, convertErr := valueFromGeneric(generic)
+
+		if parseErr != nil {
+			require.Error(t, convertErr, fixture)
+			continue
+		}
+
+		require.NoError(t, convertErr, fixture)
+		require.Equal(t, parsed, converted, fixture)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build go1.7
+// +build go1.7
+
+package faunadb
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// marshalJSON encodes the value informed as json.Marshal does, but without escaping HTML characters
+// such as <, >, and &. Escaping them is not needed since queries are never embedded in HTML.
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,10 @@
+//go:build !go1.7
+// +build !go1.7
+
+package faunadb
+
+import "encoding/json"
+
+// marshalJSON falls back to json.Marshal, escaping HTML characters, since
+// json.Encoder can not disable HTML escaping before Go 1.7.
+func marshalJSON(v interface{}) ([]byte, error) { return json.Marshal(v) }
@@ -0,0 +1,192 @@
+package faunadb
+
+var (
+	dataField   = ObjKey("data")
+	afterField  = ObjKey("after")
+	beforeField = ObjKey("before")
+	tsField     = ObjKey("ts")
+	actionField = ObjKey("action")
+
+	// Events identify their document with different keys depending on the FaunaDB version and the set paginated.
+	eventDocumentFields = []Field{ObjKey("document"), ObjKey("instance"), ObjKey("resource")}
+)
+
+// Page describes a page of a set returned by the Paginate function.
+// After and Before are nil when there are no more pages in their direction.
+type Page struct {
+	Data   ArrayV
+	After  Value
+	Before Value
+}
+
+// AfterCursor returns the After cursor of the page. It is the zero Cursor if there are no more pages after it.
+func (page Page) AfterCursor() Cursor { return Cursor{page.After} }
+
+// BeforeCursor returns the Before cursor of the page. It is the zero Cursor if there are no more pages before it.
+func (page Page) BeforeCursor() Cursor { return Cursor{page.Before} }
+
+// DecodePage extracts the data and the cursors of a page returned by the Paginate function.
+func DecodePage(value Value) (page Page, err error) {
+	if err = value.At(dataField).Get(&page.Data); err != nil {
+		return
+	}
+
+	page.After, _ = value.At(afterField).GetValue()
+	page.Before, _ = value.At(beforeField).GetValue()
+
+	return
+}
+
+// Event describes an element of a page returned by the Paginate function with the Events optional parameter.
+// Data is nil when the event carries no data, such as set events.
+type Event struct {
+	TS       int64
+	Action   string
+	Document RefV
+	Data     Value
+}
+
+/*
+DecodeEvent extracts the timestamp, action, document, and data of an event returned by the Paginate function with the
+Events optional parameter. For example:
+
+	res, _ := client.Query(Paginate(Ref("classes/spells/42"), Events(true)))
+	page, _ := DecodePage(res)
+
+	for _, value := range page.Data {
+		event, err := DecodeEvent(value)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Println(event.TS, event.Action, event.Document)
+	}
+*/
+func DecodeEvent(value Value) (event Event, err error) {
+	if err = value.At(tsField).Get(&event.TS); err != nil {
+		return
+	}
+
+	if err = value.At(actionField).Get(&event.Action); err != nil {
+		return
+	}
+
+	var document Value
+
+	for _, field := range eventDocumentFields {
+		if document, err = value.At(field).GetValue(); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		_, err = value.At(eventDocumentFields[0]).GetValue()
+		return
+	}
+
+	if err = document.Get(&event.Document); err != nil {
+		return
+	}
+
+	event.Data, _ = value.At(dataField).GetValue()
+	return
+}
+
+/*
+Paginator iterates over the pages of a set, fetching one page at a time. For example:
+
+	pages := client.AllDocuments("spells", Size(10))
+
+	for pages.HasNext() {
+		page, err := pages.Next()
+		if err != nil {
+			panic(err)
+		}
+
+		// use page's elements
+	}
+
+A Paginator can resume a previous iteration by informing the Cursor of the last page fetched as the After optional
+parameter.
+
+Pages are fetched with the client's Query method, so page fetches that fail with transient errors are retried
+according to the client's Retries configuration. When a fetch still fails, Next returns the error without losing the
+position of the iteration: the following call to Next fetches the same page again, resuming from the cursor of the last
+page fetched successfully.
+
+Paginators are not safe for concurrent use.
+*/
+type Paginator struct {
+	client  *FaunaClient
+	set     Expr
+	options []OptionalParameter
+	after   Value
+	done    bool
+}
+
+func newPaginator(client *FaunaClient, set Expr, options []OptionalParameter) *Paginator {
+	if client.pageSize > 0 {
+		// Options are applied in order, so a Size informed by the caller overrides the default
+		options = append([]OptionalParameter{Size(client.pageSize)}, options...)
+	}
+
+	return &Paginator{
+		client:  client,
+		set:     set,
+		options: options,
+	}
+}
+
+// AllDocuments creates a Paginator over all documents of the collection informed.
+// Optional parameters: TS, Size, Events, and Sources.
+func (client *FaunaClient) AllDocuments(collection string, options ...OptionalParameter) *Paginator {
+	return newPaginator(client, Documents(Collection(collection)), options)
+}
+
+// PaginateSet creates a Paginator over the set informed, usually one returned by a previous query.
+// Optional parameters: TS, Size, Events, and Sources.
+func (client *FaunaClient) PaginateSet(set SetRefV, options ...OptionalParameter) *Paginator {
+	return newPaginator(client, set, options)
+}
+
+// HasNext returns true if there are still pages to be fetched.
+func (p *Paginator) HasNext() bool { return !p.done }
+
+// Next fetches the next page of the set and returns its data. Next returns an empty page if there are no more pages.
+// If the page fails to be fetched, the paginator is left unchanged, so Next can be called again to retry it.
+func (p *Paginator) Next() (data ArrayV, err error) {
+	if p.done {
+		return ArrayV{}, nil
+	}
+
+	var res Value
+	var page Page
+
+	if res, err = p.client.Query(Paginate(p.set, p.pageOptions()...)); err != nil {
+		return
+	}
+
+	if page, err = DecodePage(res); err != nil {
+		return
+	}
+
+	p.after = page.After
+	p.done = page.After == nil
+
+	return page.Data, nil
+}
+
+// Cursor returns the cursor of the next page to be fetched. It is the zero Cursor before the first page is fetched
+// and after the last one.
+func (p *Paginator) Cursor() Cursor { return Cursor{p.after} }
+
+func (p *Paginator) pageOptions() []OptionalParameter {
+	options := make([]OptionalParameter, len(p.options), len(p.options)+1)
+	copy(options, p.options)
+
+	if p.after != nil {
+		options = append(options, After(p.after))
+	}
+
+	return options
+}
@@ -0,0 +1,262 @@
+package faunadb
+
+import (
+	"io/ioutil"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginateOverAllDocuments(t *testing.T) {
+	server := newMockServer(
+		`{"resource": {"data": [{"@ref": "classes/spells/1"}], "after": [{"@ref": "classes/spells/2"}]}}`,
+		`{"resource": {"data": [{"@ref": "classes/spells/2"}]}}`,
+	)
+	defer server.Close()
+
+	pages := server.client().AllDocuments("spells", Size(1))
+
+	var refs []RefV
+
+	for pages.HasNext() {
+		page, err := pages.Next()
+		require.NoError(t, err)
+
+		for _, value := range page {
+			refs = append(refs, value.(RefV))
+		}
+	}
+
+	require.Equal(t, []RefV{{ID: "classes/spells/1"}, {ID: "classes/spells/2"}}, refs)
+	require.Equal(t,
+		[]string{
+			`{"paginate":{"documents":{"collection":"spells"}},"size":1}`,
+			`{"after":[{"@ref":"classes/spells/2"}],"paginate":{"documents":{"collection":"spells"}},"size":1}`,
+		},
+		server.requestBodies(),
+	)
+}
+
+func TestPaginateOverSetRef(t *testing.T) {
+	server := newMockServer(
+		`{"resource": {"data": [{"@ref": "classes/spells/1"}], "after": [{"@ref": "classes/spells/2"}]}}`,
+		`{"resource": {"data": [{"@ref": "classes/spells/2"}]}}`,
+	)
+	defer server.Close()
+
+	set := SetRefV{ObjectV{"match": RefV{ID: "indexes/spells_by_element"}, "terms": StringV("fire")}}
+	pages := server.client().PaginateSet(set, Size(1))
+
+	var data ArrayV
+
+	for pages.HasNext() {
+		page, err := pages.Next()
+		require.NoError(t, err)
+
+		data = append(data, page...)
+	}
+
+	require.Equal(t, ArrayV{RefV{ID: "classes/spells/1"}, RefV{ID: "classes/spells/2"}}, data)
+	require.Equal(t,
+		[]string{
+			`{"paginate":{"@set":{"match":{"@ref":"indexes/spells_by_element"},"terms":"fire"}},"size":1}`,
+			`{"after":[{"@ref":"classes/spells/2"}],"paginate":{"@set":{"match":{"@ref":"indexes/spells_by_element"},"terms":"fire"}},"size":1}`,
+		},
+		server.requestBodies(),
+	)
+}
+
+func TestReturnEmptyPageWhenThereAreNoMorePages(t *testing.T) {
+	server := newMockServer(`{"resource": {"data": []}}`)
+	defer server.Close()
+
+	pages := server.client().AllDocuments("spells")
+
+	_, err := pages.Next()
+	require.NoError(t, err)
+	require.False(t, pages.HasNext())
+
+	page, err := pages.Next()
+	require.NoError(t, err)
+	require.Empty(t, page)
+	require.Len(t, server.requestBodies(), 1)
+}
+
+func TestDecodePageWithCursors(t *testing.T) {
+	page, err := DecodePage(ObjectV{
+		"data":   ArrayV{LongV(2)},
+		"before": ArrayV{LongV(2)},
+		"after":  ArrayV{LongV(3)},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t,
+		Page{
+			Data:   ArrayV{LongV(2)},
+			Before: ArrayV{LongV(2)},
+			After:  ArrayV{LongV(3)},
+		},
+		page,
+	)
+}
+
+func TestDecodePageWithoutCursors(t *testing.T) {
+	page, err := DecodePage(ObjectV{"data": ArrayV{LongV(1)}})
+
+	require.NoError(t, err)
+	require.Equal(t, Page{Data: ArrayV{LongV(1)}}, page)
+}
+
+func TestFailToDecodePageWithoutData(t *testing.T) {
+	_, err := DecodePage(ObjectV{"after": ArrayV{LongV(3)}})
+	require.EqualError(t, err, "Error while extracting path: data. Object key data not found")
+}
+
+func TestDecodeEventsOfDocumentHistory(t *testing.T) {
+	server := newMockServer(`{"resource": {"data": [
+		{"ts": 1, "action": "create", "document": {"@ref": "classes/spells/42"}, "data": {"name": "Fireball"}},
+		{"ts": 2, "action": "delete", "document": {"@ref": "classes/spells/42"}, "data": null}
+	]}}`)
+	defer server.Close()
+
+	res, err := server.client().Query(Paginate(Ref("classes/spells/42"), Events(true)))
+	require.NoError(t, err)
+
+	page, err := DecodePage(res)
+	require.NoError(t, err)
+
+	var events []Event
+
+	for _, value := range page.Data {
+		event, err := DecodeEvent(value)
+		require.NoError(t, err)
+
+		events = append(events, event)
+	}
+
+	require.Equal(t,
+		[]Event{
+			{TS: 1, Action: ActionCreate, Document: RefV{ID: "classes/spells/42"}, Data: ObjectV{"name": StringV("Fireball")}},
+			{TS: 2, Action: ActionDelete, Document: RefV{ID: "classes/spells/42"}, Data: NullV{}},
+		},
+		events,
+	)
+	require.Equal(t, []string{`{"events":true,"paginate":{"@ref":"classes/spells/42"}}`}, server.requestBodies())
+}
+
+func TestDecodeEventWithInstanceKey(t *testing.T) {
+	event, err := DecodeEvent(ObjectV{
+		"ts":       LongV(10),
+		"action":   StringV("add"),
+		"instance": RefV{ID: "classes/spells/1"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, Event{TS: 10, Action: "add", Document: RefV{ID: "classes/spells/1"}}, event)
+}
+
+func TestFailToDecodeEventWithoutDocument(t *testing.T) {
+	_, err := DecodeEvent(ObjectV{"ts": LongV(10), "action": StringV("add")})
+	require.EqualError(t, err, "Error while extracting path: document. Object key document not found")
+}
+
+func TestPaginateWithDefaultPageSize(t *testing.T) {
+	server := newMockServer(`{"resource": {"data": []}}`)
+	defer server.Close()
+
+	_, err := server.client(DefaultPageSize(100)).AllDocuments("spells").Next()
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"paginate":{"documents":{"collection":"spells"}},"size":100}`}, server.requestBodies())
+}
+
+func TestPaginateSizeOverridesDefaultPageSize(t *testing.T) {
+	server := newMockServer(`{"resource": {"data": []}}`)
+	defer server.Close()
+
+	set := SetRefV{ObjectV{"match": RefV{ID: "indexes/all_spells"}}}
+
+	_, err := server.client(DefaultPageSize(100)).PaginateSet(set, Size(5)).Next()
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{`{"paginate":{"@set":{"match":{"@ref":"indexes/all_spells"}}},"size":5}`},
+		server.requestBodies(),
+	)
+}
+
+const (
+	firstSpellsPage  = `{"resource": {"data": [{"@ref": "classes/spells/1"}], "after": [{"@ref": "classes/spells/2"}]}}`
+	secondSpellsPage = `{"resource": {"data": [{"@ref": "classes/spells/2"}]}}`
+	secondPageQuery  = `{"after":[{"@ref":"classes/spells/2"}],"paginate":{"documents":{"collection":"spells"}},"size":1}`
+)
+
+func requestBodiesOf(t *testing.T, requests []*http.Request) (bodies []string) {
+	for _, request := range requests {
+		body, err := ioutil.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		bodies = append(bodies, string(body))
+	}
+
+	return
+}
+
+func TestPaginatorRetriesTransientErrorsOnPage(t *testing.T) {
+	transport := &sequenceTransport{transports: []http.RoundTripper{
+		okTransport(firstSpellsPage),
+		UnavailableTransport(),
+		NetworkErrorTransport(syscall.ECONNRESET),
+		okTransport(secondSpellsPage),
+	}}
+
+	client := NewFaunaClientWithTransport("secret", transport, Retries(2, nil))
+	pages := client.AllDocuments("spells", Size(1))
+
+	var data ArrayV
+
+	for pages.HasNext() {
+		page, err := pages.Next()
+		require.NoError(t, err)
+
+		data = append(data, page...)
+	}
+
+	require.Equal(t, ArrayV{RefV{ID: "classes/spells/1"}, RefV{ID: "classes/spells/2"}}, data)
+	require.Equal(t,
+		[]string{
+			`{"paginate":{"documents":{"collection":"spells"}},"size":1}`,
+			secondPageQuery,
+			secondPageQuery,
+			secondPageQuery,
+		},
+		requestBodiesOf(t, transport.requests),
+	)
+}
+
+func TestPaginatorResumesFromLastPageAfterError(t *testing.T) {
+	transport := &sequenceTransport{transports: []http.RoundTripper{
+		okTransport(firstSpellsPage),
+		UnavailableTransport(),
+		okTransport(secondSpellsPage),
+	}}
+
+	client := NewFaunaClientWithTransport("secret", transport)
+	pages := client.AllDocuments("spells", Size(1))
+
+	page, err := pages.Next()
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{RefV{ID: "classes/spells/1"}}, page)
+
+	_, err = pages.Next()
+	require.IsType(t, Unavailable{}, err)
+	require.True(t, pages.HasNext())
+	require.Equal(t, Cursor{ArrayV{RefV{ID: "classes/spells/2"}}}, pages.Cursor())
+
+	page, err = pages.Next()
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{RefV{ID: "classes/spells/2"}}, page)
+	require.False(t, pages.HasNext())
+
+	require.Equal(t, []string{secondPageQuery, secondPageQuery}, requestBodiesOf(t, transport.requests)[1:])
+}
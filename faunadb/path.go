@@ -2,6 +2,7 @@ package faunadb
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -26,6 +27,25 @@ func (i invalidSegmentType) Error() string {
 	return fmt.Sprintf("Expected value to be %s but was a %T", i.desired, i.actual)
 }
 
+// An InvalidPathElement describes an error that occurs when extracting a field with a SelectPath field extractor
+// whose path contains elements that are neither strings nor integers.
+type InvalidPathElement struct {
+	path    path
+	element invalidPathElement
+}
+
+func (i InvalidPathElement) Error() string {
+	return fmt.Sprintf("Error while extracting path: %s. %s", i.path, i.element)
+}
+
+type invalidPathElement struct {
+	element interface{}
+}
+
+func (i invalidPathElement) Error() string {
+	return fmt.Sprintf("Expected path element to be a string or an integer but was a %T", i.element)
+}
+
 // A ValueNotFound describes an error that occurs when trying to extract a field value but the value could not be found
 type ValueNotFound struct {
 	path    path
@@ -71,11 +91,47 @@ func pathFromIndexes(indexes ...int) path {
 	return p
 }
 
+func pathFromAnyIndexes(indexes ...int) path {
+	p := make(path, len(indexes))
+
+	for i, index := range indexes {
+		p[i] = anyIndexSegment(index)
+	}
+
+	return p
+}
+
+func pathFromElements(elems ...interface{}) path {
+	p := make(path, len(elems))
+
+	for i, elem := range elems {
+		switch elem := elem.(type) {
+		case string:
+			p[i] = objectSegment(elem)
+		case int:
+			p[i] = arraySegment(elem)
+		case int64:
+			p[i] = arraySegment(elem)
+		case int32:
+			p[i] = arraySegment(elem)
+		default:
+			p[i] = invalidSegment{elem}
+		}
+	}
+
+	return p
+}
+
 func (p path) subPath(other path) path {
-	return append(p, other...)
+	sub := make(path, 0, len(p)+len(other))
+	return append(append(sub, p...), other...)
 }
 
-func (p path) get(value Value) (Value, error) {
+func (p path) get(value Value) (Value, error) { return p.extract(nil, value) }
+
+// extract transverses the value informed, reporting errors relative to
+// the parent path where the value was extracted from.
+func (p path) extract(parent path, value Value) (Value, error) {
 	var err error
 
 	next := value
@@ -84,9 +140,11 @@ func (p path) get(value Value) (Value, error) {
 		if next, err = seg.get(next); err != nil {
 			switch segErr := err.(type) {
 			case segmentNotFound:
-				return nil, ValueNotFound{p, segErr}
+				return nil, ValueNotFound{parent.subPath(p), segErr}
 			case invalidSegmentType:
-				return nil, InvalidFieldType{p, segErr}
+				return nil, InvalidFieldType{parent.subPath(p), segErr}
+			case invalidPathElement:
+				return nil, InvalidPathElement{parent.subPath(p), segErr}
 			default:
 				return nil, err
 			}
@@ -138,6 +196,10 @@ func (seg arraySegment) get(value Value) (res Value, err error) {
 
 	switch arr := value.(type) {
 	case ArrayV:
+		if index < 0 {
+			index += len(arr)
+		}
+
 		if index >= 0 && index < len(arr) {
 			res = arr[index]
 		} else {
@@ -149,3 +211,30 @@ func (seg arraySegment) get(value Value) (res Value, err error) {
 
 	return
 }
+
+// anyIndexSegment extracts an element from an array by its index, or a value from an object by its index
+// formatted as a key, such as "0".
+type anyIndexSegment int
+
+func (seg anyIndexSegment) get(value Value) (res Value, err error) {
+	switch value.(type) {
+	case ArrayV:
+		return arraySegment(seg).get(value)
+	case ObjectV:
+		if res, err = objectSegment(strconv.Itoa(int(seg))).get(value); err != nil {
+			err = segmentNotFound{"Object key", seg}
+		}
+	default:
+		err = invalidSegmentType{"an array or an object", value}
+	}
+
+	return
+}
+
+type invalidSegment struct {
+	element interface{}
+}
+
+func (seg invalidSegment) get(Value) (Value, error) { return nil, invalidPathElement{seg.element} }
+
+func (seg invalidSegment) String() string { return fmt.Sprintf("%v", seg.element) }
@@ -0,0 +1,62 @@
+package faunadb
+
+/*
+Pipeline composes query language expressions by chaining functions over a value, producing the same expression as
+nesting the functions by hand. Each step receives the result of the previous one. For example:
+
+	Pipe(Ref("classes/spells/42")).Get().Select(Arr{"data", "tags"}).Map(Lambda("tag", Casefold(Var("tag"))))
+
+Is equivalent to:
+
+	Map(Select(Arr{"data", "tags"}, Get(Ref("classes/spells/42"))), Lambda("tag", Casefold(Var("tag"))))
+
+Pipelines are immutable: each step returns a new Pipeline, so a partial pipeline can be reused as a prefix of others.
+A Pipeline is an expression itself and can be used wherever an expression is expected.
+*/
+type Pipeline struct {
+	value Expr
+}
+
+// Pipe starts a Pipeline over the value informed.
+func Pipe(value interface{}) Pipeline { return Pipeline{wrap(value)} }
+
+func (p Pipeline) expr() {}
+
+// MarshalJSON implements json.Marshaler by encoding the composed expression.
+func (p Pipeline) MarshalJSON() ([]byte, error) { return marshalJSON(p.value) }
+
+// Expr returns the composed expression.
+func (p Pipeline) Expr() Expr { return p.value }
+
+// Get retrieves the instance identified by the current value. Optional parameters: TS.
+func (p Pipeline) Get(options ...OptionalParameter) Pipeline {
+	return Pipeline{Get(p.value, options...)}
+}
+
+// Select extracts the path informed from the current value. Optional parameters: Default.
+func (p Pipeline) Select(path interface{}, options ...OptionalParameter) Pipeline {
+	return Pipeline{Select(path, p.value, options...)}
+}
+
+// Map applies the lambda informed to each element of the current value.
+func (p Pipeline) Map(lambda interface{}) Pipeline {
+	return Pipeline{Map(p.value, lambda)}
+}
+
+// Filter keeps the elements of the current value for which the lambda informed returns true.
+func (p Pipeline) Filter(lambda interface{}) Pipeline {
+	return Pipeline{Filter(p.value, lambda)}
+}
+
+// Paginate retrieves a page of the current value, a set. Optional parameters: TS, After, Before, Size,
+// Events, and Sources.
+func (p Pipeline) Paginate(options ...OptionalParameter) Pipeline {
+	return Pipeline{Paginate(p.value, options...)}
+}
+
+// Then applies a custom step to the current value, for functions without a Pipeline method. For example:
+//
+//	Pipe(Var("spells")).Then(func(spells Expr) Expr { return Take(3, spells) })
+func (p Pipeline) Then(step func(Expr) Expr) Pipeline {
+	return Pipeline{step(p.value)}
+}
@@ -0,0 +1,59 @@
+package faunadb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func assertSameJSON(t *testing.T, expected, actual Expr) {
+	require.Equal(t, toJSON(t, expected), toJSON(t, actual))
+}
+
+func TestPipelineEmitsNestedExpression(t *testing.T) {
+	lambda := Lambda("tag", Casefold(Var("tag")))
+
+	assertSameJSON(t,
+		Map(Select(Arr{"data", "tags"}, Get(Ref("classes/spells/42"))), lambda),
+		Pipe(Ref("classes/spells/42")).Get().Select(Arr{"data", "tags"}).Map(lambda),
+	)
+}
+
+func TestPipelineWithOptionalParameters(t *testing.T) {
+	assertSameJSON(t,
+		Select("data", Get(Ref("classes/spells/42"), TS(10)), Default(Obj{})),
+		Pipe(Ref("classes/spells/42")).Get(TS(10)).Select("data", Default(Obj{})),
+	)
+}
+
+func TestPipelineOverSets(t *testing.T) {
+	lambda := Lambda("ref", Get(Var("ref")))
+	isFire := Lambda("spell", Equals(Select(Arr{"data", "element"}, Var("spell")), "fire"))
+
+	assertSameJSON(t,
+		Take(2, Filter(Map(Paginate(Match(Index("all_spells")), Size(10)), lambda), isFire)),
+		Pipe(Match(Index("all_spells"))).
+			Paginate(Size(10)).
+			Map(lambda).
+			Filter(isFire).
+			Then(func(spells Expr) Expr { return Take(2, spells) }),
+	)
+}
+
+func TestPipelineIsImmutable(t *testing.T) {
+	spell := Pipe(Ref("classes/spells/42")).Get()
+
+	name := spell.Select(Arr{"data", "name"})
+	element := spell.Select(Arr{"data", "element"})
+
+	assertSameJSON(t, Get(Ref("classes/spells/42")), spell.Expr())
+	assertSameJSON(t, Select(Arr{"data", "name"}, Get(Ref("classes/spells/42"))), name)
+	assertSameJSON(t, Select(Arr{"data", "element"}, Get(Ref("classes/spells/42"))), element)
+}
+
+func TestPipelineAsExpression(t *testing.T) {
+	assertJSON(t,
+		Obj{"name": Pipe(Var("spell")).Select("name")},
+		`{"object":{"name":{"from":{"var":"spell"},"select":"name"}}}`,
+	)
+}
@@ -1,5 +1,10 @@
 package faunadb
 
+import (
+	"strconv"
+	"strings"
+)
+
 // Event's action types. Usually used as a parameter for Insert or Remove functions.
 //
 // See: https://fauna.com/documentation/queries#values-events
@@ -108,6 +113,16 @@ func Default(value interface{}) OptionalParameter {
 	}
 }
 
+// Scope is a database ref optional parameter that specifies the child database in which a schema ref should be
+// resolved, for example: Index("spells_by_name", Scope(Database("tenant"))).
+//
+// Functions that accept this optional parameter are: Database, Index, Class, and Collection.
+func Scope(database interface{}) OptionalParameter {
+	return func(fn unescapedObj) {
+		fn["scope"] = wrap(database)
+	}
+}
+
 // Separator is a string optional parameter that specifies the separator for a concat operation.
 //
 // Functions that accept this optional parameter are: Concat.
@@ -122,13 +137,19 @@ func Separator(sep interface{}) OptionalParameter {
 // Ref creates a new RefV value with the ID informed.
 //
 // See: https://fauna.com/documentation/queries#values-special_types
-func Ref(id string) Expr { return RefV{id} }
+func Ref(id string) Expr { return RefV{ID: id} }
 
 // RefClass creates a new Ref based on the class and ID informed.
 //
 // See: https://fauna.com/documentation/queries#values-special_types
 func RefClass(classRef, id interface{}) Expr { return fn2("ref", classRef, "id", id) }
 
+// Ref2 creates a new Ref based on the collection name and ID informed.
+// It is a shortcut for RefClass(Collection(collection), id).
+//
+// See: https://fauna.com/documentation/queries#values-special_types
+func Ref2(collection, id string) Expr { return RefClass(Collection(collection), id) }
+
 // Null creates a NullV value.
 //
 // See: https://fauna.com/documentation/queries#values
@@ -148,7 +169,15 @@ func Do(exprs ...interface{}) Expr { return fn1("do", varargs(exprs...)) }
 // See: https://fauna.com/documentation/queries#basic_forms
 func If(cond, then, elze interface{}) Expr { return fn3("if", cond, "then", then, "else", elze) }
 
+// CreateIfAbsent evaluates the create expression informed only if the ref informed does not exist, returning true
+// if it was created, or false if it already existed. It is used by idempotent migrations, for example:
+//
+//	CreateIfAbsent(Collection("spells"), CreateCollection(Obj{"name": "spells"}))
+func CreateIfAbsent(ref, create interface{}) Expr { return If(Exists(ref), false, Do(create, true)) }
+
 // Lambda creates an anonymous function. Mostly used with Collection functions.
+// The varName can be an array of names to destructure the lambda argument, for example:
+// Lambda(Arr{"ts", "ref"}, Var("ref")) or Lambda([]string{"ts", "ref"}, Var("ref")).
 //
 // See: https://fauna.com/documentation/queries#basic_forms
 func Lambda(varName, expr interface{}) Expr { return fn2("lambda", varName, "expr", expr) }
@@ -163,6 +192,63 @@ func At(timestamp, expr interface{}) Expr { return fn2("at", timestamp, "expr",
 // See: https://fauna.com/documentation/queries#basic_forms
 func Let(bindings Obj, in interface{}) Expr { return fn2("let", unescapedBindings(bindings), "in", in) }
 
+// LetFn binds a value to a variable that is passed to the in function, so that variable references are checked
+// by the compiler rather than by their names. For example:
+//
+//	LetFn(1, func(x BoundVar) Expr {
+//		return Add(x, 1)
+//	})
+//
+// Variables are named after the number of LetFn calls nested in the in expression, starting with _letfn0 for the
+// innermost one, so LetFn calls can be nested and the same expression always encodes to the same JSON. Variables
+// bound with Let or Lambda should not use the _letfn prefix.
+//
+// See: https://fauna.com/documentation/queries#basic_forms
+func LetFn(value interface{}, in func(BoundVar) Expr) Expr {
+	v := BoundVar{new(string)}
+	body := in(v)
+
+	*v.name = letFnPrefix + strconv.Itoa(letFnDepth(body))
+	return Let(Obj{*v.name: value}, body)
+}
+
+const letFnPrefix = "_letfn"
+
+// letFnDepth returns the number of LetFn calls nested in the expression informed, found by the names they bind.
+// Obj and Arr literals are wrapped first, so calls nested in them, or in the bindings of Let, are also found.
+func letFnDepth(expr Expr) (depth int) {
+	switch e := expr.(type) {
+	case Obj, Arr:
+		return letFnDepth(wrap(e))
+	case unescapedObj:
+		if bindings, ok := e["let"].(unescapedObj); ok {
+			for name := range bindings {
+				if !strings.HasPrefix(name, letFnPrefix) {
+					continue
+				}
+
+				if n, err := strconv.Atoi(name[len(letFnPrefix):]); err == nil && n+1 > depth {
+					depth = n + 1
+				}
+			}
+		}
+
+		for _, value := range e {
+			if n := letFnDepth(value); n > depth {
+				depth = n
+			}
+		}
+	case unescapedArr:
+		for _, value := range e {
+			if n := letFnDepth(value); n > depth {
+				depth = n
+			}
+		}
+	}
+
+	return
+}
+
 // Var refers to a value of a variable on the current lexical scope.
 //
 // See: https://fauna.com/documentation/queries#basic_forms
@@ -222,6 +308,15 @@ func Prepend(elems, coll interface{}) Expr { return fn2("prepend", elems, "colle
 // See: https://fauna.com/documentation/queries#collection_functions
 func Append(elems, coll interface{}) Expr { return fn2("append", elems, "collection", coll) }
 
+// Reduce applies the lambda expression on each element of a collection or set, accumulating the results.
+// The lambda receives the accumulator, starting with initial, and the element, and returns the new accumulator:
+// Reduce(Lambda(Arr{"acc", "elem"}, Add(Var("acc"), Var("elem"))), 0, Arr{1, 2, 3}) evaluates to 6.
+//
+// See: https://fauna.com/documentation/queries#collection_functions
+func Reduce(lambda, initial, coll interface{}) Expr {
+	return fn3("reduce", lambda, "initial", initial, "collection", coll)
+}
+
 // Read
 
 // Get retrieves the instance identified by the ref informed. Optional parameters: TS.
@@ -260,12 +355,17 @@ func Create(ref, params interface{}) Expr { return fn2("create", ref, "params",
 // See: https://fauna.com/documentation/queries#write_functions
 func CreateClass(params interface{}) Expr { return fn1("create_class", params) }
 
+// CreateCollection creates an new collection, on FaunaDB versions that name classes collections.
+//
+// See: https://fauna.com/documentation/queries#write_functions
+func CreateCollection(params interface{}) Expr { return fn1("create_collection", params) }
+
 // CreateDatabase creates an new database.
 //
 // See: https://fauna.com/documentation/queries#write_functions
 func CreateDatabase(params interface{}) Expr { return fn1("create_database", params) }
 
-// CreateIndex creates an new index.
+// CreateIndex creates an new index. See CreateIndexConfig for describing its parameters.
 //
 // See: https://fauna.com/documentation/queries#write_functions
 func CreateIndex(params interface{}) Expr { return fn1("create_index", params) }
@@ -280,11 +380,24 @@ func CreateKey(params interface{}) Expr { return fn1("create_key", params) }
 // See: https://fauna.com/documentation/queries#write_functions
 func CreateFunction(params interface{}) Expr { return fn1("create_function", params) }
 
+// CreateRole creates an new role. See Privilege and Membership for describing its access rules.
+//
+// See: https://fauna.com/documentation/queries#write_functions
+func CreateRole(params interface{}) Expr { return fn1("create_role", params) }
+
 // Update the instance informed.
 //
 // See: https://fauna.com/documentation/queries#write_functions
 func Update(ref, params interface{}) Expr { return fn2("update", ref, "params", params) }
 
+// Upsert updates the instance informed if it exists, otherwise creates it with the params informed.
+// It is a shortcut for If(Exists(ref), Update(ref, params), Create(ref, params)).
+//
+// See: https://fauna.com/documentation/queries#write_functions
+func Upsert(ref, params interface{}) Expr {
+	return If(Exists(ref), Update(ref, params), Create(ref, params))
+}
+
 // Replace the instance informed.
 //
 // See: https://fauna.com/documentation/queries#write_functions
@@ -322,6 +435,28 @@ func Concat(terms interface{}, options ...OptionalParameter) Expr {
 // See: https://fauna.com/documentation/queries#string_functions
 func Casefold(str interface{}) Expr { return fn1("casefold", str) }
 
+// ContainsStr returns true if the string contains the search string informed.
+//
+// See: https://fauna.com/documentation/queries#string_functions
+func ContainsStr(str, search interface{}) Expr { return fn2("containsstr", str, "search", search) }
+
+// ContainsStrRegex returns true if the string contains a match for the regular expression pattern informed.
+//
+// See: https://fauna.com/documentation/queries#string_functions
+func ContainsStrRegex(str, pattern interface{}) Expr {
+	return fn2("containsstrregex", str, "pattern", pattern)
+}
+
+// StartsWith returns true if the string starts with the search string informed.
+//
+// See: https://fauna.com/documentation/queries#string_functions
+func StartsWith(str, search interface{}) Expr { return fn2("startswith", str, "search", search) }
+
+// EndsWith returns true if the string ends with the search string informed.
+//
+// See: https://fauna.com/documentation/queries#string_functions
+func EndsWith(str, search interface{}) Expr { return fn2("endswith", str, "search", search) }
+
 // Time and Date
 
 // Time constructs a time from a ISO 8601 offset date/time string.
@@ -372,6 +507,23 @@ func Difference(sets ...interface{}) Expr { return fn1("difference", varargs(set
 // See: https://fauna.com/documentation/queries#sets
 func Distinct(set interface{}) Expr { return fn1("distinct", set) }
 
+// Singleton returns the set containing only the ref informed.
+//
+// See: https://fauna.com/documentation/queries#sets
+func Singleton(ref interface{}) Expr { return fn1("singleton", ref) }
+
+// ContainsRef returns true if the ref informed is a member of the set informed, for example in role membership
+// predicates: ContainsRef(Match(Index("admins")), Var("ref")). It is equivalent to
+// Exists(Intersection(set, Singleton(ref))).
+//
+// See: https://fauna.com/documentation/queries#sets
+func ContainsRef(set, ref interface{}) Expr { return Exists(Intersection(set, Singleton(ref))) }
+
+// Documents returns the set of all documents in the collection informed.
+//
+// See: https://fauna.com/documentation/queries#sets
+func Documents(collection interface{}) Expr { return fn1("documents", collection) }
+
 // Join derives a set of resources from target by applying each instance in source to target.
 //
 // See: https://fauna.com/documentation/queries#sets
@@ -402,20 +554,35 @@ func Identify(ref, password interface{}) Expr { return fn2("identify", ref, "pas
 // See: https://fauna.com/documentation/queries#misc_functions
 func NextID() Expr { return fn1("next_id", NullV{}) }
 
-// Database creates a new database ref.
+// Database creates a new database ref. Optional parameters: Scope.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func Database(name interface{}, options ...OptionalParameter) Expr { return fn1("database", name, options...) }
+
+// Index creates a new index ref. Optional parameters: Scope.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func Index(name interface{}, options ...OptionalParameter) Expr { return fn1("index", name, options...) }
+
+// Class creates a new class ref. Optional parameters: Scope.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func Class(name interface{}, options ...OptionalParameter) Expr { return fn1("class", name, options...) }
+
+// Collection creates a new collection ref. Optional parameters: Scope.
 //
 // See: https://fauna.com/documentation/queries#misc_functions
-func Database(name interface{}) Expr { return fn1("database", name) }
+func Collection(name interface{}, options ...OptionalParameter) Expr { return fn1("collection", name, options...) }
 
-// Index creates a new index ref.
+// Function creates a new user defined function ref. Optional parameters: Scope.
 //
 // See: https://fauna.com/documentation/queries#misc_functions
-func Index(name interface{}) Expr { return fn1("index", name) }
+func Function(name interface{}, options ...OptionalParameter) Expr { return fn1("function", name, options...) }
 
-// Class creates a new class ref.
+// Role creates a new role ref. Optional parameters: Scope.
 //
 // See: https://fauna.com/documentation/queries#misc_functions
-func Class(name interface{}) Expr { return fn1("class", name) }
+func Role(name interface{}, options ...OptionalParameter) Expr { return fn1("role", name, options...) }
 
 // Equals checks if all args are equivalents.
 //
@@ -452,6 +619,33 @@ func Divide(args ...interface{}) Expr { return fn1("divide", varargs(args...)) }
 // See: https://fauna.com/documentation/queries#misc_functions
 func Modulo(args ...interface{}) Expr { return fn1("modulo", varargs(args...)) }
 
+// Max returns the greatest of the numbers informed. The numbers are always sent as an array, so a single argument is
+// not taken as a collection; use SetMax to compute the greatest number of an array or a set.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func Max(args ...interface{}) Expr { return fn1("max", args) }
+
+// Min returns the smallest of the numbers informed. The numbers are always sent as an array, so a single argument is
+// not taken as a collection; use SetMin to compute the smallest number of an array or a set.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func Min(args ...interface{}) Expr { return fn1("min", args) }
+
+// SetMax reduces an array or a set of numbers to its greatest number.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func SetMax(collection interface{}) Expr { return fn1("max", collection) }
+
+// SetMin reduces an array or a set of numbers to its smallest number.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func SetMin(collection interface{}) Expr { return fn1("min", collection) }
+
+// Floor rounds the number informed down to the nearest integer.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func Floor(num interface{}) Expr { return fn1("floor", num) }
+
 // LT returns true if each specified value compares as less than the ones following it,
 // and false otherwise.
 //
@@ -497,3 +691,120 @@ func Not(boolean interface{}) Expr { return fn1("not", boolean) }
 func Select(path, value interface{}, options ...OptionalParameter) Expr {
 	return fn2("select", path, "from", value, options...)
 }
+
+// SelectWithDefault traverses into the value informed returning the value under the desired path, or the default
+// value informed when the path is absent. The default is sent as an expression and only evaluated by FaunaDB when
+// the path is missing, so it may be an expensive query such as a Get. It is equivalent to Select with the Default
+// optional parameter.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func SelectWithDefault(path, value, defaultValue interface{}) Expr {
+	return Select(path, value, Default(defaultValue))
+}
+
+// SelectAll traverses into the value informed returning an array with every value under the desired path. Unlike
+// Select, arrays along the path are traversed element by element, so the path can extract a field from each object
+// of an array, as the ObjKeyEach field extractor does for decoded values.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func SelectAll(path, value interface{}) Expr { return fn2("select_all", path, "from", value) }
+
+// CollectionOf extracts the collection ref of the document ref informed.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func CollectionOf(ref interface{}) Expr { return Select("collection", ref) }
+
+// ClassOf extracts the class ref of the instance ref informed, on FaunaDB versions that name collections classes.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func ClassOf(ref interface{}) Expr { return Select("class", ref) }
+
+// DatabaseOf extracts the ref of the child database in which the ref informed is scoped. It returns null for refs
+// of the current database.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func DatabaseOf(ref interface{}) Expr { return Select("database", ref, Default(NullV{})) }
+
+// IDOf extracts the ID of the ref informed.
+//
+// See: https://fauna.com/documentation/queries#misc_functions
+func IDOf(ref interface{}) Expr { return Select("id", ref) }
+
+// Conversion
+
+// ToObject converts an array of [key, value] pairs into an object, such as the result of ToArray.
+//
+// See: https://fauna.com/documentation/queries#conversion_functions
+func ToObject(pairs interface{}) Expr { return fn1("to_object", pairs) }
+
+// ToArray converts an object into an array of [key, value] pairs, the inverse of ToObject.
+//
+// See: https://fauna.com/documentation/queries#conversion_functions
+func ToArray(obj interface{}) Expr { return fn1("to_array", obj) }
+
+// Type predicates
+
+// IsNumber returns true if the expression informed evaluates to a number.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsNumber(expr interface{}) Expr { return fn1("is_number", expr) }
+
+// IsDouble returns true if the expression informed evaluates to a double.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsDouble(expr interface{}) Expr { return fn1("is_double", expr) }
+
+// IsInteger returns true if the expression informed evaluates to an integer.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsInteger(expr interface{}) Expr { return fn1("is_integer", expr) }
+
+// IsString returns true if the expression informed evaluates to a string.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsString(expr interface{}) Expr { return fn1("is_string", expr) }
+
+// IsBoolean returns true if the expression informed evaluates to a boolean.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsBoolean(expr interface{}) Expr { return fn1("is_boolean", expr) }
+
+// IsNull returns true if the expression informed evaluates to null.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsNull(expr interface{}) Expr { return fn1("is_null", expr) }
+
+// IsArray returns true if the expression informed evaluates to an array.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsArray(expr interface{}) Expr { return fn1("is_array", expr) }
+
+// IsObject returns true if the expression informed evaluates to an object.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsObject(expr interface{}) Expr { return fn1("is_object", expr) }
+
+// IsRef returns true if the expression informed evaluates to a ref.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsRef(expr interface{}) Expr { return fn1("is_ref", expr) }
+
+// IsSet returns true if the expression informed evaluates to a set.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsSet(expr interface{}) Expr { return fn1("is_set", expr) }
+
+// IsTimestamp returns true if the expression informed evaluates to a timestamp.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsTimestamp(expr interface{}) Expr { return fn1("is_timestamp", expr) }
+
+// IsDate returns true if the expression informed evaluates to a date.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsDate(expr interface{}) Expr { return fn1("is_date", expr) }
+
+// IsBytes returns true if the expression informed evaluates to a byte array.
+//
+// See: https://fauna.com/documentation/queries#type_functions
+func IsBytes(expr interface{}) Expr { return fn1("is_bytes", expr) }
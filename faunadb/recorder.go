@@ -0,0 +1,272 @@
+package faunadb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+const redacted = "<redacted>"
+
+// Responses larger than this are passed on to the client without being recorded.
+const maxRecordedBytes = 16 << 20
+
+// Keys whose values are replaced by a placeholder in recordings, such as the secrets returned by CreateKey.
+var redactedKeys = map[string]bool{"secret": true, "password": true}
+
+// recording is a single line of the NDJSON format written by Recorder and read by ReplayTransport.
+type recording struct {
+	Request  json.RawMessage `json:"request"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+/*
+Recorder configures the FaunaClient structure to write each request sent to FaunaDB, along with its response, to
+the writer informed. Each exchange is written as a JSON object on its own line, holding the request body, the
+response status, and the response body. Credentials are never recorded: the Authorization header is left out, and
+the values of "secret" and "password" keys are redacted from both bodies.
+
+Responses are passed on to the client as they are read, and recorded when their body is closed. Responses larger
+than 16 MiB, and responses not read to the end, such as result streams closed early, are not recorded.
+
+Recordings can be served back by ReplayTransport, turning real traffic into offline tests.
+*/
+func Recorder(writer io.Writer) ClientConfig {
+	return func(cli *FaunaClient) { cli.recorder = writer }
+}
+
+/*
+ReplayTransport creates an http.RoundTripper that replies to requests with the responses recorded by Recorder,
+read from the reader informed. Requests are matched by their body, with the same redactions applied while
+recording. Identical requests are replied in the order they were recorded. For example:
+
+	transport, err := ReplayTransport(file)
+	if err != nil {
+		panic(err)
+	}
+
+	client := NewFaunaClientWithTransport("secret", transport)
+
+Requests without a matching recording fail.
+*/
+func ReplayTransport(reader io.Reader) (http.RoundTripper, error) {
+	replay := &replayTransport{responses: make(map[string][]recording)}
+	decoder := json.NewDecoder(reader)
+
+	for i := 1; ; i++ {
+		var rec recording
+
+		if err := decoder.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Error while reading recording %d: %s", i, err)
+		}
+
+		key := string(redactBody(rec.Request))
+		replay.responses[key] = append(replay.responses[key], rec)
+	}
+
+	return replay, nil
+}
+
+type recordingTransport struct {
+	next   http.RoundTripper
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func newRecordingTransport(next http.RoundTripper, writer io.Writer) *recordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &recordingTransport{next: next, writer: writer}
+}
+
+func (rt *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var requestBody []byte
+
+	if request.Body != nil {
+		var err error
+
+		if requestBody, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+
+		_ = request.Body.Close()
+		request.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	response, err := rt.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &recordingBody{transport: rt, request: requestBody, status: response.StatusCode, closer: response.Body}
+	body.reader = io.TeeReader(response.Body, &body.recorded)
+	response.Body = body
+
+	return response, nil
+}
+
+func (rt *recordingTransport) record(rec recording) {
+	line, err := marshalJSON(rec)
+
+	if err == nil {
+		rt.mutex.Lock()
+		_, _ = rt.writer.Write(append(line, '\n'))
+		rt.mutex.Unlock()
+	}
+}
+
+// recordingBody copies a response body into a limitedBuffer as it is read, recording the response once it is closed.
+type recordingBody struct {
+	transport *recordingTransport
+	request   []byte
+	status    int
+	reader    io.Reader
+	closer    io.Closer
+	recorded  limitedBuffer
+	eof       bool
+	closed    bool
+}
+
+func (body *recordingBody) Read(p []byte) (n int, err error) {
+	n, err = body.reader.Read(p)
+
+	if err == io.EOF {
+		body.eof = true
+	}
+
+	return
+}
+
+func (body *recordingBody) Close() error {
+	err := body.closer.Close()
+
+	if !body.closed {
+		body.closed = true
+
+		if recorded := body.recorded.Bytes(); !body.recorded.exceeded && (body.eof || completeJSON(recorded)) {
+			body.transport.record(recording{
+				Request:  redactBody(body.request),
+				Status:   body.status,
+				Response: redactBody(recorded),
+			})
+		}
+	}
+
+	return err
+}
+
+// limitedBuffer is a bytes.Buffer that discards everything written once it would grow past maxRecordedBytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	exceeded bool
+}
+
+func (buffer *limitedBuffer) Write(p []byte) (int, error) {
+	if buffer.exceeded || buffer.Len()+len(p) > maxRecordedBytes {
+		buffer.exceeded = true
+		return len(p), nil
+	}
+
+	return buffer.Buffer.Write(p)
+}
+
+// completeJSON reports whether the body informed starts with a complete JSON value, even if it was not read to the end.
+func completeJSON(body []byte) bool {
+	var value interface{}
+	return json.NewDecoder(bytes.NewReader(body)).Decode(&value) == nil
+}
+
+type replayTransport struct {
+	mutex     sync.Mutex
+	responses map[string][]recording
+}
+
+func (rt *replayTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if request.Body != nil {
+		var err error
+
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	key := string(redactBody(body))
+
+	rt.mutex.Lock()
+	recorded := rt.responses[key]
+	if len(recorded) > 0 {
+		rt.responses[key] = recorded[1:]
+	}
+	rt.mutex.Unlock()
+
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("Error while replaying request: No recording found for request %s", key)
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(recorded[0].Status),
+		StatusCode:    recorded[0].Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(recorded[0].Response)),
+		ContentLength: int64(len(recorded[0].Response)),
+		Request:       request,
+	}, nil
+}
+
+// redactBody normalizes a JSON body, replacing the values of redacted keys. Bodies that are not valid JSON are
+// recorded as JSON strings, and empty bodies as null.
+func redactBody(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return json.RawMessage("null")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var parsed interface{}
+	var normalized []byte
+	var err error
+
+	if err = decoder.Decode(&parsed); err == nil {
+		normalized, err = marshalJSON(redactValue(parsed))
+	}
+
+	if err != nil {
+		normalized, _ = marshalJSON(string(body))
+	}
+
+	return normalized
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			if _, isString := elem.(string); isString && redactedKeys[key] {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(elem)
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = redactValue(elem)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,185 @@
+package faunadb
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRequestsAndResponses(t *testing.T) {
+	server := newMockServer(
+		`{"resource": {"ref": {"@ref": "keys/1"}, "secret": "fnAAAAAAAAAAAA"}}`,
+		`{"resource": [1, 2]}`,
+	)
+	defer server.Close()
+
+	var records bytes.Buffer
+	client := server.client(Recorder(&records))
+
+	_, err := client.Query(CreateKey(Obj{"database": Database("prydain"), "role": "server"}))
+	require.NoError(t, err)
+
+	value, err := client.Query(Arr{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{LongV(1), LongV(2)}, value)
+
+	require.Equal(t,
+		`{"request":{"create_key":{"object":{"database":{"database":"prydain"},"role":"server"}}},"status":200,`+
+			`"response":{"resource":{"ref":{"@ref":"keys/1"},"secret":"<redacted>"}}}`+"\n"+
+			`{"request":[1,2],"status":200,"response":{"resource":[1,2]}}`+"\n",
+		records.String(),
+	)
+	require.NotContains(t, records.String(), "secret:")
+}
+
+func TestRecordStreamsAsTheyAreRead(t *testing.T) {
+	reader, writer := io.Pipe()
+
+	transport := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: reader, Request: request}, nil
+	})
+
+	var records bytes.Buffer
+	client := NewFaunaClientWithTransport("secret", transport, Recorder(&records))
+
+	go func() { _, _ = writer.Write([]byte(`{"resource": [1, `)) }()
+
+	stream, err := client.QueryStream(Arr{1, 2})
+	require.NoError(t, err)
+
+	value, err := stream.Next()
+	require.NoError(t, err)
+	require.Equal(t, LongV(1), value)
+	require.Empty(t, records.String())
+
+	go func() {
+		_, _ = writer.Write([]byte(`2]}`))
+		_ = writer.Close()
+	}()
+
+	value, err = stream.Next()
+	require.NoError(t, err)
+	require.Equal(t, LongV(2), value)
+
+	_, err = stream.Next()
+	require.Equal(t, io.EOF, err)
+
+	require.Equal(t, `{"request":[1,2],"status":200,"response":{"resource":[1,2]}}`+"\n", records.String())
+}
+
+func TestDoNotRecordResponsesNotReadToTheEnd(t *testing.T) {
+	reader, writer := io.Pipe()
+
+	transport := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: reader, Request: request}, nil
+	})
+
+	var records bytes.Buffer
+	client := NewFaunaClientWithTransport("secret", transport, Recorder(&records))
+
+	go func() { _, _ = writer.Write([]byte(`{"resource": [1, `)) }()
+
+	stream, err := client.QueryStream(NullV{})
+	require.NoError(t, err)
+
+	_, err = stream.Next()
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+	require.Empty(t, records.String())
+}
+
+func TestDoNotRecordResponsesLargerThanTheLimit(t *testing.T) {
+	large := `{"resource": "` + strings.Repeat("a", maxRecordedBytes) + `"}`
+
+	server := newMockServer(large)
+	defer server.Close()
+
+	var records bytes.Buffer
+
+	value, err := server.client(Recorder(&records)).Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, StringV(strings.Repeat("a", maxRecordedBytes)), value)
+	require.Empty(t, records.String())
+}
+
+func TestRedactPasswordsFromRequests(t *testing.T) {
+	server := newMockServer(`{"resource": {"secret": "fnBBBBBBBBBBBB"}}`)
+	defer server.Close()
+
+	var records bytes.Buffer
+
+	_, err := server.client(Recorder(&records)).Query(Login(Ref("classes/users/1"), Obj{"password": "hunter2"}))
+	require.NoError(t, err)
+	require.NotContains(t, records.String(), "hunter2")
+	require.NotContains(t, records.String(), "fnBBBBBBBBBBBB")
+}
+
+func TestReplayRecordedTraffic(t *testing.T) {
+	server := newMockServer(`{"resource": "first"}`, `{"resource": "second"}`, `{"resource": "third"}`)
+	defer server.Close()
+
+	var records bytes.Buffer
+	recorder := server.client(Recorder(&records))
+
+	for _, expr := range []Expr{Var("x"), Var("x"), Var("y")} {
+		_, err := recorder.Query(expr)
+		require.NoError(t, err)
+	}
+
+	transport, err := ReplayTransport(&records)
+	require.NoError(t, err)
+
+	client := NewFaunaClientWithTransport("another-secret", transport)
+
+	for _, test := range []struct {
+		expr     Expr
+		expected Value
+	}{
+		{Var("y"), StringV("third")},
+		{Var("x"), StringV("first")},
+		{Var("x"), StringV("second")},
+	} {
+		value, err := client.Query(test.expr)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, value)
+	}
+
+	_, err = client.Query(Var("x"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Error while replaying request: No recording found for request {"var":"x"}`)
+}
+
+func TestReplayRecordedErrors(t *testing.T) {
+	records := `{"request":{"get":{"@ref":"classes/spells/42"}},"status":404,` +
+		`"response":{"errors":[{"position":[],"code":"instance not found","description":"Instance not found."}]}}`
+
+	transport, err := ReplayTransport(strings.NewReader(records))
+	require.NoError(t, err)
+
+	_, err = NewFaunaClientWithTransport("secret", transport).Query(Get(Ref("classes/spells/42")))
+	require.IsType(t, NotFound{}, err)
+}
+
+func TestReplayRequestsWithRedactedValues(t *testing.T) {
+	records := `{"request":{"login":{"@ref":"classes/users/1"},"params":{"object":{"password":"<redacted>"}}},` +
+		`"status":200,"response":{"resource":{"secret":"<redacted>"}}}`
+
+	transport, err := ReplayTransport(strings.NewReader(records))
+	require.NoError(t, err)
+
+	value, err := NewFaunaClientWithTransport("secret", transport).Query(
+		Login(Ref("classes/users/1"), Obj{"password": "hunter2"}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"secret": StringV("<redacted>")}, value)
+}
+
+func TestFailToReadInvalidRecordings(t *testing.T) {
+	_, err := ReplayTransport(strings.NewReader(`{"request": null, "status": 200, "response": null}` + "\n" + `not json`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Error while reading recording 2:")
+}
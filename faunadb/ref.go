@@ -0,0 +1,215 @@
+package faunadb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Native collections of the refs built by schema functions, such as Collection("spells").
+var nativeCollections = map[string]string{
+	"Class":      "classes",
+	"Collection": "collections",
+	"Database":   "databases",
+	"Index":      "indexes",
+	"Function":   "functions",
+	"Role":       "roles",
+}
+
+/*
+ParseRef parses the string representation of a ref back into a RefV. Both legacy and structured representations
+are supported:
+
+	ParseRef("classes/spells/42")              // RefV{ID: "classes/spells/42"}
+	ParseRef(`Ref(Collection("spells"), "42")`) // RefV{ID: "42", Collection: &RefV{ID: "spells", Collection: ...}}
+
+Structured representations may reference schema refs scoped to child databases, such as
+Collection("spells", Database("prydain")).
+*/
+func ParseRef(str string) (ref RefV, err error) {
+	trimmed := strings.TrimSpace(str)
+
+	if strings.HasSuffix(trimmed, ")") {
+		parser := refParser{input: trimmed}
+
+		if ref, err = parser.parseRef(); err == nil && parser.pos < len(parser.input) {
+			err = fmt.Errorf("Unexpected %q", parser.input[parser.pos:])
+		}
+	} else {
+		ref, err = parseLegacyRef(trimmed)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("Error while parsing ref %q: %s", str, err)
+	}
+
+	return
+}
+
+func parseLegacyRef(str string) (RefV, error) {
+	if str == "" {
+		return RefV{}, errors.New("Empty ref")
+	}
+
+	for _, segment := range strings.Split(str, "/") {
+		if segment == "" {
+			return RefV{}, errors.New("Empty path segment")
+		}
+
+		if strings.IndexFunc(segment, func(r rune) bool { return unicode.IsSpace(r) || r == '"' }) >= 0 {
+			return RefV{}, fmt.Errorf("Invalid path segment %q", segment)
+		}
+	}
+
+	return RefV{ID: str}, nil
+}
+
+type refParser struct {
+	input string
+	pos   int
+}
+
+// parseRef parses either Ref(<schema ref>, "<id>") or a schema ref.
+func (p *refParser) parseRef() (ref RefV, err error) {
+	var name string
+
+	if name, err = p.parseCall(); err != nil {
+		return
+	}
+
+	if name != "Ref" {
+		return p.parseSchemaRef(name)
+	}
+
+	var collection RefV
+
+	if collection, err = p.parseAnySchemaRef(); err != nil {
+		return
+	}
+
+	if err = p.expect(','); err != nil {
+		return
+	}
+
+	if ref.ID, err = p.parseString(); err != nil {
+		return
+	}
+
+	ref.Collection = &collection
+	err = p.expect(')')
+	return
+}
+
+func (p *refParser) parseAnySchemaRef() (ref RefV, err error) {
+	var name string
+
+	if name, err = p.parseCall(); err == nil {
+		ref, err = p.parseSchemaRef(name)
+	}
+
+	return
+}
+
+// parseSchemaRef parses the arguments of a schema ref function: ("<name>") or ("<name>", Database("<name>")).
+func (p *refParser) parseSchemaRef(function string) (ref RefV, err error) {
+	native, found := nativeCollections[function]
+	if !found {
+		return RefV{}, fmt.Errorf("Unknown ref function %s", function)
+	}
+
+	if ref.ID, err = p.parseString(); err != nil {
+		return
+	}
+
+	ref.Collection = &RefV{ID: native}
+
+	if p.skipSpaces(); p.peek() == ',' {
+		p.pos++
+
+		var database RefV
+
+		if database, err = p.parseAnySchemaRef(); err != nil {
+			return
+		}
+
+		if database.Collection.ID != "databases" {
+			return RefV{}, fmt.Errorf("Expected a database ref but got %s", database.Collection.ID)
+		}
+
+		ref.Database = &database
+	}
+
+	err = p.expect(')')
+	return
+}
+
+// parseCall parses a function name followed by an opening parenthesis.
+func (p *refParser) parseCall() (string, error) {
+	p.skipSpaces()
+	start := p.pos
+
+	for p.pos < len(p.input) && unicode.IsLetter(rune(p.input[p.pos])) {
+		p.pos++
+	}
+
+	name := p.input[start:p.pos]
+
+	if name == "" {
+		return "", fmt.Errorf("Expected a function at position %d", start)
+	}
+
+	return name, p.expect('(')
+}
+
+func (p *refParser) parseString() (string, error) {
+	p.skipSpaces()
+
+	if p.peek() != '"' {
+		return "", fmt.Errorf("Expected a string at position %d", p.pos)
+	}
+
+	end := p.pos + 1
+	for end < len(p.input) && p.input[end] != '"' {
+		if p.input[end] == '\\' {
+			end++
+		}
+		end++
+	}
+
+	if end >= len(p.input) {
+		return "", fmt.Errorf("Unterminated string at position %d", p.pos)
+	}
+
+	str, err := strconv.Unquote(p.input[p.pos : end+1])
+	if err != nil {
+		return "", fmt.Errorf("Invalid string at position %d", p.pos)
+	}
+
+	p.pos = end + 1
+	return str, nil
+}
+
+func (p *refParser) expect(char byte) error {
+	if p.skipSpaces(); p.peek() != char {
+		return fmt.Errorf("Expected '%c' at position %d", char, p.pos)
+	}
+
+	p.pos++
+	return nil
+}
+
+func (p *refParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+
+	return 0
+}
+
+func (p *refParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
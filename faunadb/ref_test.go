@@ -0,0 +1,80 @@
+package faunadb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLegacyRef(t *testing.T) {
+	for _, str := range []string{"classes/users/123", "databases/prydain", "indexes/all_spells", "keys/1"} {
+		ref, err := ParseRef(str)
+		require.NoError(t, err)
+		require.Equal(t, RefV{ID: str}, ref)
+	}
+}
+
+func TestParseStructuredRef(t *testing.T) {
+	ref, err := ParseRef(`Ref(Collection("users"), "123")`)
+	require.NoError(t, err)
+	require.Equal(t,
+		RefV{ID: "123", Collection: &RefV{ID: "users", Collection: &RefV{ID: "collections"}}},
+		ref,
+	)
+}
+
+func TestParseSchemaRefs(t *testing.T) {
+	for str, expected := range map[string]RefV{
+		`Collection("users")`:  {ID: "users", Collection: &RefV{ID: "collections"}},
+		`Class("users")`:       {ID: "users", Collection: &RefV{ID: "classes"}},
+		`Index("all_users")`:   {ID: "all_users", Collection: &RefV{ID: "indexes"}},
+		`Database("prydain")`:  {ID: "prydain", Collection: &RefV{ID: "databases"}},
+		`Function("double")`:   {ID: "double", Collection: &RefV{ID: "functions"}},
+		`Role("admin")`:        {ID: "admin", Collection: &RefV{ID: "roles"}},
+		` Index( "a \"b\"" ) `: {ID: `a "b"`, Collection: &RefV{ID: "indexes"}},
+	} {
+		ref, err := ParseRef(str)
+		require.NoError(t, err, str)
+		require.Equal(t, expected, ref, str)
+	}
+}
+
+func TestParseRefScopedToDatabase(t *testing.T) {
+	ref, err := ParseRef(`Ref(Collection("spells", Database("prydain")), "42")`)
+	require.NoError(t, err)
+	require.Equal(t,
+		RefV{
+			ID: "42",
+			Collection: &RefV{
+				ID:         "spells",
+				Collection: &RefV{ID: "collections"},
+				Database:   &RefV{ID: "prydain", Collection: &RefV{ID: "databases"}},
+			},
+		},
+		ref,
+	)
+}
+
+func TestParsedRefIsUsableAsExpr(t *testing.T) {
+	ref, err := ParseRef("classes/users/123")
+	require.NoError(t, err)
+
+	assertJSON(t, Get(ref), `{"get":{"@ref":"classes/users/123"}}`)
+}
+
+func TestFailToParseMalformedRefs(t *testing.T) {
+	for str, expected := range map[string]string{
+		``:                                `Error while parsing ref "": Empty ref`,
+		`classes//123`:                    `Error while parsing ref "classes//123": Empty path segment`,
+		`classes/my users`:                `Error while parsing ref "classes/my users": Invalid path segment "my users"`,
+		`Ref(Collection("users"), 123)`:   `Error while parsing ref "Ref(Collection(\"users\"), 123)": Expected a string at position 25`,
+		`Ref(Collection("users") "123")`:  `Error while parsing ref "Ref(Collection(\"users\") \"123\")": Expected ',' at position 24`,
+		`Thing("users")`:                  `Error while parsing ref "Thing(\"users\")": Unknown ref function Thing`,
+		`Collection("users", Index("x"))`: `Error while parsing ref "Collection(\"users\", Index(\"x\"))": Expected a database ref but got indexes`,
+		`Collection("users"))`:            `Error while parsing ref "Collection(\"users\"))": Unexpected ")"`,
+		`Collection("users)`:              `Error while parsing ref "Collection(\"users)": Unterminated string at position 11`,
+	} {
+		_, err := ParseRef(str)
+		require.EqualError(t, err, expected, str)
+	}
+}
@@ -1,32 +1,85 @@
 package faunadb
 
-import "reflect"
+import (
+	"reflect"
+	"sync"
+)
+
+type structField struct {
+	value   reflect.Value
+	options tagOptions
+}
+
+// fieldInfo describes an exported struct field by its index in the struct and its fauna tag.
+type fieldInfo struct {
+	index   int
+	name    string
+	options tagOptions
+}
+
+// structTypes caches the exported fields of struct types, so their tags are only parsed once per type.
+var structTypes = struct {
+	sync.RWMutex
+	fields map[reflect.Type][]fieldInfo
+}{fields: make(map[reflect.Type][]fieldInfo)}
 
 func structToMap(aStruct reflect.Value) map[string]interface{} {
-	res := make(map[string]interface{}, aStruct.NumField())
+	fields := exportedStructFields(aStruct.Type())
+	res := make(map[string]interface{}, len(fields))
 
-	for key, value := range exportedStructFields(aStruct) {
-		res[key] = value.Interface()
+	for _, info := range fields {
+		res[info.name] = encodeStructField(info.field(aStruct))
 	}
 
 	return res
 }
 
-func exportedStructFields(aStruct reflect.Value) map[string]reflect.Value {
-	fields := make(map[string]reflect.Value)
-	aStructType := aStruct.Type()
+func (info fieldInfo) field(aStruct reflect.Value) structField {
+	return structField{aStruct.Field(info.index), info.options}
+}
+
+func exportedStructFields(aStructType reflect.Type) []fieldInfo {
+	structTypes.RLock()
+	fields, found := structTypes.fields[aStructType]
+	structTypes.RUnlock()
+
+	if found {
+		return fields
+	}
+
+	fields = computeStructFields(aStructType)
+
+	structTypes.Lock()
+	structTypes.fields[aStructType] = fields
+	structTypes.Unlock()
+
+	return fields
+}
+
+func computeStructFields(aStructType reflect.Type) []fieldInfo {
+	var fields []fieldInfo
+	positions := make(map[string]int)
+
+	for i, size := 0, aStructType.NumField(); i < size; i++ {
+		field := aStructType.Field(i)
+
+		if field.PkgPath != "" { // Unexported field
+			continue
+		}
 
-	for i, size := 0, aStruct.NumField(); i < size; i++ {
-		field := aStruct.Field(i)
+		fieldName, options := parseTag(field)
 
-		if !field.CanInterface() {
+		if fieldName == "-" {
 			continue
 		}
 
-		fieldName := fieldName(aStructType.Field(i))
+		info := fieldInfo{i, fieldName, options}
 
-		if fieldName != "-" {
-			fields[fieldName] = field
+		if position, found := positions[fieldName]; found { // The last field with the same name wins
+			fields[position] = info
+		} else {
+			positions[fieldName] = len(fields)
+			fields = append(fields, info)
 		}
 	}
 
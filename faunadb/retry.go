@@ -0,0 +1,76 @@
+package faunadb
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"time"
+)
+
+/*
+Retries configures the FaunaClient structure to retry queries that fail with transient errors up to the number of
+times informed. Transient errors are Unavailable errors, timeouts, and connections to FaunaDB refused or reset. Other
+failures to send the request, such as invalid TLS certificates, are not retried.
+Before each retry, the client waits for the duration returned by the backoff function for the retry number, starting
+at 1. A nil backoff retries immediately.
+
+Retried queries may have been executed by FaunaDB before failing. Queries that must not be applied twice, such as
+creates, should use the IdempotencyKey query configuration.
+*/
+func Retries(retries int, backoff func(retry int) time.Duration) ClientConfig {
+	return func(cli *FaunaClient) {
+		cli.retries = retries
+		cli.backoff = backoff
+	}
+}
+
+// ExponentialBackoff creates a backoff function for the Retries configuration that doubles the wait time on each
+// retry, starting with the base duration informed and limited to the max duration informed.
+func ExponentialBackoff(base, max time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		wait := base
+
+		for i := 1; i < retry && wait < max; i++ {
+			wait *= 2
+		}
+
+		if wait > max {
+			wait = max
+		}
+
+		return wait
+	}
+}
+
+func isTransientError(err error) bool {
+	switch e := err.(type) {
+	case Unavailable:
+		return true
+	case *url.Error:
+		return isTransientNetworkError(e.Err)
+	default:
+		return false
+	}
+}
+
+// isTransientNetworkError reports whether the error informed, returned by a http.RoundTripper, is a timeout or a
+// connection refused or reset.
+func isTransientNetworkError(err error) bool {
+	if timeout, ok := err.(interface {
+		Timeout() bool
+	}); ok && timeout.Timeout() {
+		return true
+	}
+
+	switch e := err.(type) {
+	case *net.OpError:
+		return isTransientNetworkError(e.Err)
+	case *os.SyscallError:
+		return isTransientNetworkError(e.Err)
+	case syscall.Errno:
+		return e == syscall.ECONNREFUSED || e == syscall.ECONNRESET
+	default:
+		return false
+	}
+}
@@ -0,0 +1,176 @@
+package faunadb
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceTransport replies to each request with the next transport informed, repeating the last one when exhausted.
+type sequenceTransport struct {
+	mutex      sync.Mutex
+	transports []http.RoundTripper
+	requests   []*http.Request
+}
+
+func (seq *sequenceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	seq.mutex.Lock()
+	next := len(seq.requests)
+	if next >= len(seq.transports) {
+		next = len(seq.transports) - 1
+	}
+
+	seq.requests = append(seq.requests, request)
+	seq.mutex.Unlock()
+
+	return seq.transports[next].RoundTrip(request)
+}
+
+func okTransport(body string) http.RoundTripper {
+	return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    request,
+		}, nil
+	})
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	var retries []int
+
+	transport := &sequenceTransport{transports: []http.RoundTripper{
+		UnavailableTransport(),
+		NetworkErrorTransport(syscall.ECONNRESET),
+		okTransport(`{"resource": "created"}`),
+	}}
+
+	client := NewFaunaClientWithTransport("secret", transport, Retries(3, func(retry int) time.Duration {
+		retries = append(retries, retry)
+		return 0
+	}))
+
+	value, err := client.Query(Create(Class("spells"), Obj{}), IdempotencyKey("create-spell-1"))
+	require.NoError(t, err)
+	require.Equal(t, StringV("created"), value)
+	require.Equal(t, []int{1, 2}, retries)
+
+	require.Len(t, transport.requests, 3)
+	for _, request := range transport.requests {
+		require.Equal(t, "create-spell-1", request.Header.Get("Idempotency-Key"))
+	}
+}
+
+func TestDoNotRetryNonTransientErrors(t *testing.T) {
+	transport := &sequenceTransport{transports: []http.RoundTripper{
+		ErrorTransport(http.StatusBadRequest, QueryError{Code: "invalid expression"}),
+		okTransport(`{"resource": null}`),
+	}}
+
+	client := NewFaunaClientWithTransport("secret", transport, Retries(3, nil))
+
+	_, err := client.Query(NullV{})
+	require.IsType(t, BadRequest{}, err)
+	require.Len(t, transport.requests, 1)
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryTimeoutsAndRefusedConnections(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}
+
+	transport := &sequenceTransport{transports: []http.RoundTripper{
+		NetworkErrorTransport(refused),
+		NetworkErrorTransport(timeoutError{}),
+		NetworkErrorTransport(syscall.ECONNRESET),
+		okTransport(`{"resource": null}`),
+	}}
+
+	_, err := NewFaunaClientWithTransport("secret", transport, Retries(3, nil)).Query(NullV{})
+	require.NoError(t, err)
+	require.Len(t, transport.requests, 4)
+}
+
+func TestDoNotRetryCertificateErrors(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var retries int
+
+	client := NewFaunaClient("secret", Endpoint(server.URL), Retries(2, func(int) time.Duration {
+		retries++
+		return 0
+	}))
+
+	_, err := client.Query(NullV{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "certificate")
+	require.Zero(t, retries)
+}
+
+func TestDoNotRetryReplayMisses(t *testing.T) {
+	replay, err := ReplayTransport(strings.NewReader(""))
+	require.NoError(t, err)
+
+	var retries int
+
+	client := NewFaunaClientWithTransport("secret", replay, Retries(2, func(int) time.Duration {
+		retries++
+		return 0
+	}))
+
+	_, err = client.Query(NullV{})
+	require.Error(t, err)
+	require.Zero(t, retries)
+}
+
+func TestGiveUpAfterMaxRetries(t *testing.T) {
+	transport := &sequenceTransport{transports: []http.RoundTripper{UnavailableTransport()}}
+	client := NewFaunaClientWithTransport("secret", transport, Retries(2, nil))
+
+	_, err := client.Query(NullV{})
+	require.IsType(t, Unavailable{}, err)
+	require.Len(t, transport.requests, 3)
+}
+
+func TestDoNotRetryByDefault(t *testing.T) {
+	transport := &sequenceTransport{transports: []http.RoundTripper{UnavailableTransport()}}
+
+	_, err := NewFaunaClientWithTransport("secret", transport).Query(NullV{})
+	require.IsType(t, Unavailable{}, err)
+	require.Len(t, transport.requests, 1)
+}
+
+func TestIdempotencyKeyIsNotSentByDefault(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	_, err := server.client().Query(NullV{})
+	require.NoError(t, err)
+	require.NotContains(t, server.requestHeader(0), "Idempotency-Key")
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	require.Equal(t, 100*time.Millisecond, backoff(1))
+	require.Equal(t, 200*time.Millisecond, backoff(2))
+	require.Equal(t, 400*time.Millisecond, backoff(3))
+	require.Equal(t, 800*time.Millisecond, backoff(4))
+	require.Equal(t, time.Second, backoff(5))
+	require.Equal(t, time.Second, backoff(50))
+}
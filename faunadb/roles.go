@@ -0,0 +1,95 @@
+package faunadb
+
+/*
+Privilege describes the actions a role is allowed to perform on a resource, such as a class, an index, or a function.
+It encodes to the privilege object expected by CreateRole. For example:
+
+	CreateRole(Obj{
+		"name": "librarian",
+		"privileges": Arr{
+			Privilege{
+				Resource: Class("spells"),
+				Actions:  PrivilegeActions{Read: Query(Lambda("ref", Select(Arr{"data", "public"}, Get(Var("ref"))))), Write: true},
+			},
+		},
+	})
+
+See: https://fauna.com/documentation/security#roles
+*/
+type Privilege struct {
+	Resource interface{}
+	Actions  PrivilegeActions
+}
+
+/*
+PrivilegeActions describes the actions allowed by a Privilege. Each action can be set to a boolean, allowing or
+denying the action, or to a predicate wrapped with Query, such as Query(Lambda("ref", true)), which allows the
+action when the predicate returns true. Actions left as nil are not encoded.
+*/
+type PrivilegeActions struct {
+	Create           interface{}
+	Read             interface{}
+	Write            interface{}
+	Delete           interface{}
+	HistoryRead      interface{}
+	HistoryWrite     interface{}
+	UnrestrictedRead interface{}
+	Call             interface{}
+}
+
+/*
+Membership describes which instances are members of a role. Instances of the class informed as Resource are members
+of the role when the optional Predicate returns true. Like privilege actions, the predicate is a Lambda expression
+receiving the instance ref, wrapped with Query, such as Query(Lambda("ref", true)).
+
+See: https://fauna.com/documentation/security#roles
+*/
+type Membership struct {
+	Resource  interface{}
+	Predicate interface{}
+}
+
+func (privilege Privilege) expr()      {}
+func (actions PrivilegeActions) expr() {}
+func (membership Membership) expr()    {}
+
+// MarshalJSON implements json.Marshaler by encoding the privilege as a privilege object.
+func (privilege Privilege) MarshalJSON() ([]byte, error) {
+	return marshalJSON(Obj{
+		"resource": privilege.Resource,
+		"actions":  privilege.Actions,
+	})
+}
+
+// MarshalJSON implements json.Marshaler by encoding only the actions set.
+func (actions PrivilegeActions) MarshalJSON() ([]byte, error) {
+	obj := Obj{}
+
+	setAction := func(name string, action interface{}) {
+		if action != nil {
+			obj[name] = action
+		}
+	}
+
+	setAction("create", actions.Create)
+	setAction("read", actions.Read)
+	setAction("write", actions.Write)
+	setAction("delete", actions.Delete)
+	setAction("history_read", actions.HistoryRead)
+	setAction("history_write", actions.HistoryWrite)
+	setAction("unrestricted_read", actions.UnrestrictedRead)
+	setAction("call", actions.Call)
+
+	return marshalJSON(obj)
+}
+
+// MarshalJSON implements json.Marshaler by encoding the membership as a membership object.
+func (membership Membership) MarshalJSON() ([]byte, error) {
+	obj := Obj{"resource": membership.Resource}
+
+	if membership.Predicate != nil {
+		obj["predicate"] = membership.Predicate
+	}
+
+	return marshalJSON(obj)
+}
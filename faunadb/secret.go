@@ -0,0 +1,70 @@
+package faunadb
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+SecretProvider configures the FaunaClient structure to fetch its secret from the function informed, such as one
+reading short-lived tokens from a vault, instead of using the secret given to NewFaunaClient. The secret fetched is
+reused for the duration informed, measured with the client's Clock, and fetched again by the first query sent after
+it expires. A zero duration fetches the secret for every query.
+
+Queries fail with the error returned by the provider, without being sent. Secrets replaced with SetSecret are used
+until the duration expires. Session clients, including the ones created by ScopedQuery, keep the secret they were
+created with.
+*/
+func SecretProvider(provider func() (string, error), ttl time.Duration) ClientConfig {
+	return func(cli *FaunaClient) {
+		cli.secretProvider = provider
+		cli.secretTTL = ttl
+	}
+}
+
+// secretSource fetches the secret of credentials created with a SecretProvider. Its expiration is protected by
+// the mutex of the credentials.
+type secretSource struct {
+	fetch   func() (string, error)
+	ttl     time.Duration
+	now     func() time.Time
+	expires time.Time
+}
+
+func (source *secretSource) fresh(now time.Time) bool {
+	return !source.expires.IsZero() && now.Before(source.expires)
+}
+
+// refresh fetches a new secret from the credentials' provider when the current one has expired.
+func (c *credentials) refresh(scheme AuthScheme) error {
+	if c.source == nil {
+		return nil
+	}
+
+	c.mutex.RLock()
+	fresh := c.source.fresh(c.source.now())
+	c.mutex.RUnlock()
+
+	if fresh {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.source.now()
+
+	if c.source.fresh(now) { // Fetched by a concurrent query
+		return nil
+	}
+
+	secret, err := c.source.fetch()
+	if err != nil {
+		return fmt.Errorf("Error while fetching secret: %s", err)
+	}
+
+	c.key, c.header = secret, scheme(secret)
+	c.source.expires = now.Add(c.source.ttl)
+
+	return nil
+}
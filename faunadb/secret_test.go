@@ -0,0 +1,161 @@
+package faunadb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider returns a secret provider that returns a new secret, secret-1, secret-2, and so on, on each call.
+func countingProvider(calls *int) func() (string, error) {
+	return func() (string, error) {
+		*calls++
+		return fmt.Sprintf("secret-%d", *calls), nil
+	}
+}
+
+func TestFetchSecretFromProvider(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	var calls int
+	client := NewFaunaClient("", Endpoint(server.URL), SecretProvider(countingProvider(&calls), time.Minute))
+
+	_, err := client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("secret-1"), server.requestHeader(0).Get("Authorization"))
+	require.Equal(t, 1, calls)
+}
+
+func TestReuseProvidedSecretWithinTTL(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	var calls int
+	now := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+	client := server.client(
+		SecretProvider(countingProvider(&calls), time.Minute),
+		Clock(func() time.Time { return now }),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Query(NullV{})
+		require.NoError(t, err)
+
+		now = now.Add(20 * time.Second)
+	}
+
+	_, err := client.Query(NullV{})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, calls)
+	require.Equal(t, BasicAuth("secret-1"), server.requestHeader(2).Get("Authorization"))
+	require.Equal(t, BasicAuth("secret-2"), server.requestHeader(3).Get("Authorization"))
+}
+
+func TestFetchSecretForEveryQueryWithoutTTL(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	var calls int
+	client := server.client(SecretProvider(countingProvider(&calls), 0))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Query(NullV{})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, calls)
+	require.Equal(t, BasicAuth("secret-2"), server.requestHeader(1).Get("Authorization"))
+}
+
+func TestReportSecretProviderErrors(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	provider := func() (string, error) { return "", errors.New("Vault is sealed") }
+	client := server.client(SecretProvider(provider, time.Minute))
+
+	_, err := client.Query(NullV{})
+	require.EqualError(t, err, "Error while fetching secret: Vault is sealed")
+	require.Empty(t, server.requestBodies())
+}
+
+func TestRetryFailedSecretFetches(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	fail := true
+	provider := func() (string, error) {
+		if fail {
+			return "", errors.New("Vault is unavailable")
+		}
+
+		return "fetched", nil
+	}
+
+	client := server.client(SecretProvider(provider, time.Minute))
+
+	_, err := client.Query(NullV{})
+	require.Error(t, err)
+
+	fail = false
+
+	_, err = client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("fetched"), server.requestHeader(0).Get("Authorization"))
+}
+
+func TestSetSecretReplacesProvidedSecretUntilTTL(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	var calls int
+	now := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+	client := server.client(
+		SecretProvider(countingProvider(&calls), time.Minute),
+		Clock(func() time.Time { return now }),
+	)
+
+	client.SetSecret("replaced")
+
+	_, err := client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("replaced"), server.requestHeader(0).Get("Authorization"))
+
+	now = now.Add(time.Minute)
+
+	_, err = client.Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("secret-1"), server.requestHeader(1).Get("Authorization"))
+}
+
+func TestSessionClientsDoNotUseSecretProvider(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	var calls int
+	client := server.client(SecretProvider(countingProvider(&calls), time.Minute))
+
+	_, err := client.NewSessionClient("session").Query(NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("session"), server.requestHeader(0).Get("Authorization"))
+	require.Equal(t, 0, calls)
+}
+
+func TestScopedQueryWithProvidedSecret(t *testing.T) {
+	server := newMockServer(`{"resource": null}`)
+	defer server.Close()
+
+	var calls int
+	client := server.client(SecretProvider(countingProvider(&calls), time.Minute))
+
+	_, err := client.ScopedQuery("tenant", NullV{})
+	require.NoError(t, err)
+	require.Equal(t, BasicAuth("secret-1:tenant:admin"), server.requestHeader(0).Get("Authorization"))
+}
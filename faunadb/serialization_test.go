@@ -72,6 +72,22 @@ func TestNotSerializeUintBiggerThanMaxInt(t *testing.T) {
 	require.Contains(t, err.Error(), "Error while encoding number to json: Uint value exceeds maximum int64")
 }
 
+func TestNotSerializeNaNDoubles(t *testing.T) {
+	_, err := json.Marshal(Obj{"x": math.NaN()})
+	require.Contains(t, err.Error(), "Error while encoding number to json: Double value NaN is not finite")
+
+	_, err = ExprJSON(Add(DoubleV(math.NaN()), 1))
+	require.Contains(t, err.Error(), "Error while encoding number to json: Double value NaN is not finite")
+}
+
+func TestNotSerializeInfiniteDoubles(t *testing.T) {
+	_, err := json.Marshal(Arr{float32(1), math.Inf(1)})
+	require.Contains(t, err.Error(), "Error while encoding number to json: Double value +Inf is not finite")
+
+	_, err = ExprJSON(Obj{"x": DoubleV(math.Inf(-1))})
+	require.Contains(t, err.Error(), "Error while encoding number to json: Double value -Inf is not finite")
+}
+
 func TestFailtToSerializeUnsupportedTypes(t *testing.T) {
 	c := make(chan string)
 	_, err := json.Marshal(Obj{"x": c})
@@ -154,6 +170,96 @@ func TestSerializeStructWithTags(t *testing.T) {
 	)
 }
 
+func TestSerializeStructWithEpochFields(t *testing.T) {
+	type event struct {
+		Seconds int64  `fauna:"seconds,unix"`
+		Millis  int64  `fauna:"millis,unixmilli"`
+		Micros  uint64 `fauna:"micros,unixmicro"`
+		Plain   int64  `fauna:"plain"`
+	}
+
+	assertJSON(t,
+		Obj{"data": event{1483264800, 1483264800123, 1483264800123456, 1483264800}},
+		`{"object":{"data":{"object":{`+
+			`"micros":{"@ts":"2017-01-01T10:00:00.123456Z"},`+
+			`"millis":{"@ts":"2017-01-01T10:00:00.123Z"},`+
+			`"plain":1483264800,`+
+			`"seconds":{"@ts":"2017-01-01T10:00:00Z"}`+
+			`}}}}`,
+	)
+}
+
+func TestSerializeStructWithBoolnumFields(t *testing.T) {
+	type user struct {
+		Active   bool `fauna:"active,boolnum"`
+		Disabled bool `fauna:"disabled,boolnum"`
+		Admin    bool `fauna:"admin"`
+	}
+
+	assertJSON(t,
+		Obj{"data": user{true, false, true}},
+		`{"object":{"data":{"object":{"active":1,"admin":true,"disabled":0}}}}`,
+	)
+}
+
+func TestSerializeStructWithDurationFields(t *testing.T) {
+	type session struct {
+		TTL      time.Duration `fauna:"ttl,seconds"`
+		Half     time.Duration `fauna:"half,seconds"`
+		Millis   time.Duration `fauna:"millis,milliseconds"`
+		Untagged time.Duration `fauna:"untagged"`
+	}
+
+	assertJSON(t,
+		Obj{"data": session{time.Hour, 500 * time.Millisecond, 1500 * time.Millisecond, time.Millisecond}},
+		`{"object":{"data":{"object":{"half":0.5,"millis":1500,"ttl":3600,"untagged":1000000}}}}`,
+	)
+}
+
+func TestSerializeStructWithSetRefFields(t *testing.T) {
+	type spellbook struct {
+		Spells SetRefV `fauna:"spells"`
+	}
+
+	assertJSON(t,
+		Obj{"data": spellbook{SetRefV{map[string]Value{"match": RefV{ID: "indexes/all_spells"}}}}},
+		`{"object":{"data":{"object":{"spells":{"@set":{"match":{"@ref":"indexes/all_spells"}}}}}}}`,
+	)
+}
+
+type testElement int
+
+const (
+	testFire testElement = iota
+	testWater
+	testEarth
+)
+
+func init() {
+	RegisterEnum(testFire, map[int]string{int(testFire): "fire", int(testWater): "water"})
+}
+
+func TestSerializeRegisteredEnums(t *testing.T) {
+	type spell struct {
+		Element testElement `fauna:"element"`
+	}
+
+	assertJSON(t,
+		Obj{"element": testWater, "elements": []testElement{testFire, testWater}, "spell": spell{testFire}},
+		`{"object":{"element":"water","elements":["fire","water"],"spell":{"object":{"element":"fire"}}}}`,
+	)
+}
+
+func TestFailToSerializeUnknownEnumValues(t *testing.T) {
+	_, err := json.Marshal(Obj{"element": testEarth})
+	require.Contains(t, err.Error(), "Error while encoding enum faunadb.testElement: Unknown value 2")
+}
+
+func TestRegisterEnumRequiresIntegerTypes(t *testing.T) {
+	require.Panics(t, func() { RegisterEnum("fire", map[int]string{}) })
+	require.Panics(t, func() { RegisterEnum(nil, map[int]string{}) })
+}
+
 func TestSerializeStructWithIgnoredFields(t *testing.T) {
 	type user struct {
 		Name string `fauna:"name"`
@@ -217,6 +323,20 @@ func TestSerializeStructWithEmbeddedStructs(t *testing.T) {
 	)
 }
 
+func TestSerializeRefV(t *testing.T) {
+	assertJSON(t,
+		RefV{ID: "classes/spells/42"},
+		`{"@ref":"classes/spells/42"}`,
+	)
+}
+
+func TestSerializeStructuredRefV(t *testing.T) {
+	assertJSON(t,
+		RefV{ID: "42", Collection: &RefV{ID: "spells", Collection: &RefV{ID: "collections"}}},
+		`{"@ref":{"collection":{"@ref":{"collection":{"@ref":"collections"},"id":"spells"}},"id":"42"}}`,
+	)
+}
+
 func TestSerializeRef(t *testing.T) {
 	assertJSON(t,
 		RefClass(Ref("classes/spells"), "42"),
@@ -224,6 +344,13 @@ func TestSerializeRef(t *testing.T) {
 	)
 }
 
+func TestSerializeRef2(t *testing.T) {
+	assertJSON(t,
+		Ref2("spells", "42"),
+		`{"id":"42","ref":{"collection":"spells"}}`,
+	)
+}
+
 func TestSerializeCreate(t *testing.T) {
 	assertJSON(t,
 		Create(Ref("classes/spells"), Obj{
@@ -242,6 +369,15 @@ func TestSerializeUpdate(t *testing.T) {
 	)
 }
 
+func TestSerializeUpsert(t *testing.T) {
+	assertJSON(t,
+		Upsert(Ref("classes/spells/123"), Obj{"data": Obj{"name": "fire"}}),
+		`{"else":{"create":{"@ref":"classes/spells/123"},"params":{"object":{"data":{"object":{"name":"fire"}}}}},`+
+			`"if":{"exists":{"@ref":"classes/spells/123"}},`+
+			`"then":{"params":{"object":{"data":{"object":{"name":"fire"}}}},"update":{"@ref":"classes/spells/123"}}}`,
+	)
+}
+
 func TestSerializeReplace(t *testing.T) {
 	assertJSON(t,
 		Replace(Ref("classes/spells/123"), Obj{
@@ -310,6 +446,41 @@ func TestSerializeCreateIndex(t *testing.T) {
 	)
 }
 
+func TestSerializeCreateIndexWithConfig(t *testing.T) {
+	assertJSON(t,
+		CreateIndex(CreateIndexConfig{
+			Name: "spells_by_element",
+			Source: IndexSource{
+				Class:  Class("spells"),
+				Fields: map[string]interface{}{"element": Query(Lambda("spell", Casefold(Select(Arr{"data", "element"}, Var("spell")))))},
+			},
+			Terms:  []IndexTerm{{Binding: "element"}},
+			Values: []IndexValue{{Field: []string{"data", "cost"}, Reverse: true}, {Field: []string{"ref"}}},
+			Unique: true,
+		}),
+		`{"create_index":{"object":{`+
+			`"name":"spells_by_element",`+
+			`"source":{"object":{"class":{"class":"spells"},"fields":{"object":{"element":{"query":`+
+			`{"expr":{"casefold":{"from":{"var":"spell"},"select":["data","element"]}},"lambda":"spell"}}}}}},`+
+			`"terms":[{"object":{"binding":"element"}}],`+
+			`"unique":true,`+
+			`"values":[{"object":{"field":["data","cost"],"reverse":true}},{"object":{"field":["ref"]}}]`+
+			`}}}`,
+	)
+}
+
+func TestSerializeCreateIndexWithMinimalConfig(t *testing.T) {
+	assertJSON(t,
+		CreateIndex(CreateIndexConfig{
+			Name:   "spells_by_name",
+			Source: Class("spells"),
+			Terms:  []IndexTerm{{Field: []string{"data", "name"}}},
+		}),
+		`{"create_index":{"object":{"name":"spells_by_name","source":{"class":"spells"},`+
+			`"terms":[{"object":{"field":["data","name"]}}]}}}`,
+	)
+}
+
 func TestSerializeCreateKey(t *testing.T) {
 	assertJSON(t,
 		CreateKey(Obj{
@@ -320,6 +491,55 @@ func TestSerializeCreateKey(t *testing.T) {
 	)
 }
 
+func TestSerializeCreateRole(t *testing.T) {
+	assertJSON(t,
+		CreateRole(Obj{
+			"name": "librarian",
+			"membership": Arr{
+				Membership{
+					Resource:  Class("users"),
+					Predicate: Query(Lambda("ref", Select(Arr{"data", "librarian"}, Get(Var("ref"))))),
+				},
+			},
+			"privileges": Arr{
+				Privilege{
+					Resource: Class("spells"),
+					Actions:  PrivilegeActions{Read: true, Write: Query(Lambda("ref", false))},
+				},
+			},
+		}),
+		`{"create_role":{"object":{`+
+			`"membership":[{"object":{"predicate":{"query":{"expr":{"from":{"get":{"var":"ref"}},"select":["data","librarian"]},"lambda":"ref"}},`+
+			`"resource":{"class":"users"}}}],`+
+			`"name":"librarian",`+
+			`"privileges":[{"object":{"actions":{"object":{"read":true,"write":{"query":{"expr":false,"lambda":"ref"}}}},"resource":{"class":"spells"}}}]`+
+			`}}}`,
+	)
+}
+
+func TestSerializeMembershipWithoutPredicate(t *testing.T) {
+	assertJSON(t,
+		Membership{Resource: Class("users")},
+		`{"object":{"resource":{"class":"users"}}}`,
+	)
+}
+
+func TestSerializeNativeValues(t *testing.T) {
+	assertJSON(t,
+		Create(Ref("classes/spells"), Obj{"data": Obj{
+			"n":     5,
+			"cost":  1.5,
+			"ok":    true,
+			"name":  "fire",
+			"none":  Null(),
+			"since": time.Unix(1, 2).UTC(),
+		}}),
+		`{"create":{"@ref":"classes/spells"},"params":{"object":{"data":{"object":{`+
+			`"cost":1.5,"n":5,"name":"fire","none":null,"ok":true,"since":{"@ts":"1970-01-01T00:00:01.000000002Z"}`+
+			`}}}}}`,
+	)
+}
+
 func TestSerializeNull(t *testing.T) {
 	assertJSON(t, Null(), `null`)
 }
@@ -366,6 +586,93 @@ func TestSerializeLet(t *testing.T) {
 	)
 }
 
+func TestSerializeLetFn(t *testing.T) {
+	expr := LetFn(Ref("classes/spells/42"), func(spell BoundVar) Expr {
+		return Exists(spell)
+	})
+
+	assertJSON(t, expr, `{"in":{"exists":{"var":"_letfn0"}},"let":{"_letfn0":{"@ref":"classes/spells/42"}}}`)
+}
+
+func TestSerializeNestedLetFn(t *testing.T) {
+	expr := LetFn(1, func(x BoundVar) Expr {
+		return Map(Arr{1, 2}, Lambda("n", LetFn(Var("n"), func(y BoundVar) Expr {
+			return LetFn(2, func(z BoundVar) Expr {
+				return Add(x, y, z)
+			})
+		})))
+	})
+
+	assertJSON(t, expr,
+		`{"in":{"collection":[1,2],"map":{"expr":{"in":{"in":{"add":[{"var":"_letfn2"},{"var":"_letfn1"},`+
+			`{"var":"_letfn0"}]},"let":{"_letfn0":2}},"let":{"_letfn1":{"var":"n"}}},"lambda":"n"}},"let":{"_letfn2":1}}`,
+	)
+}
+
+func TestSerializeLetFnNestedInObj(t *testing.T) {
+	expr := LetFn(1, func(a BoundVar) Expr {
+		return Obj{"a": LetFn(2, func(b BoundVar) Expr {
+			return Add(a, b)
+		})}
+	})
+
+	assertJSON(t, expr,
+		`{"in":{"object":{"a":{"in":{"add":[{"var":"_letfn1"},{"var":"_letfn0"}]},"let":{"_letfn0":2}}}},`+
+			`"let":{"_letfn1":1}}`,
+	)
+}
+
+func TestSerializeLetFnNestedInArr(t *testing.T) {
+	expr := LetFn(1, func(a BoundVar) Expr {
+		return Arr{Obj{"a": LetFn(2, func(b BoundVar) Expr { return Add(a, b) })}}
+	})
+
+	assertJSON(t, expr,
+		`{"in":[{"object":{"a":{"in":{"add":[{"var":"_letfn1"},{"var":"_letfn0"}]},"let":{"_letfn0":2}}}}],`+
+			`"let":{"_letfn1":1}}`,
+	)
+}
+
+func TestSerializeLetFnNestedInLetBindings(t *testing.T) {
+	expr := LetFn(1, func(a BoundVar) Expr {
+		return Let(Obj{"sum": LetFn(2, func(b BoundVar) Expr { return Add(a, b) })}, Var("sum"))
+	})
+
+	assertJSON(t, expr,
+		`{"in":{"in":{"var":"sum"},"let":{"sum":{"in":{"add":[{"var":"_letfn1"},{"var":"_letfn0"}]},`+
+			`"let":{"_letfn0":2}}}},"let":{"_letfn1":1}}`,
+	)
+}
+
+func TestSerializeSiblingLetFn(t *testing.T) {
+	expr := Arr{
+		LetFn(1, func(x BoundVar) Expr { return x }),
+		LetFn(2, func(y BoundVar) Expr { return y }),
+	}
+
+	assertJSON(t, expr,
+		`[{"in":{"var":"_letfn0"},"let":{"_letfn0":1}},{"in":{"var":"_letfn0"},"let":{"_letfn0":2}}]`,
+	)
+}
+
+func TestSerializeLetFnDeterministically(t *testing.T) {
+	build := func() Expr {
+		return LetFn(Ref("classes/spells/42"), func(spell BoundVar) Expr {
+			return LetFn(Get(spell), func(instance BoundVar) Expr {
+				return Select(Arr{"data", "name"}, instance)
+			})
+		})
+	}
+
+	first, err := ExprJSON(build())
+	require.NoError(t, err)
+
+	second, err := ExprJSON(build())
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+}
+
 func TestSerializeIf(t *testing.T) {
 	assertJSON(t,
 		If(true, "exists", "does not exists"),
@@ -398,6 +705,18 @@ func TestSerializeLambda(t *testing.T) {
 	)
 }
 
+func TestSerializeLambdaWithMultipleParams(t *testing.T) {
+	assertJSON(t,
+		Lambda(Arr{"ts", "ref"}, Var("ref")),
+		`{"expr":{"var":"ref"},"lambda":["ts","ref"]}`,
+	)
+
+	assertJSON(t,
+		Lambda([]string{"ts", "ref"}, Var("ref")),
+		`{"expr":{"var":"ref"},"lambda":["ts","ref"]}`,
+	)
+}
+
 func TestSerializeMap(t *testing.T) {
 	assertJSON(t,
 		Map(Arr{1, 2, 3}, Lambda("x", Var("x"))),
@@ -419,6 +738,19 @@ func TestSerializeFilter(t *testing.T) {
 	)
 }
 
+func TestSerializeReduce(t *testing.T) {
+	assertJSON(t,
+		Reduce(
+			Lambda(Arr{"total", "spell"}, Add(Var("total"), Select(Arr{"data", "cost"}, Var("spell")))),
+			0,
+			Arr{Obj{"data": Obj{"cost": 10}}, Obj{"data": Obj{"cost": 5}}},
+		),
+		`{"collection":[{"object":{"data":{"object":{"cost":10}}}},{"object":{"data":{"object":{"cost":5}}}}],`+
+			`"initial":0,`+
+			`"reduce":{"expr":{"add":[{"var":"total"},{"from":{"var":"spell"},"select":["data","cost"]}]},"lambda":["total","spell"]}}`,
+	)
+}
+
 func TestSerializeTake(t *testing.T) {
 	assertJSON(t,
 		Take(2, Arr{1, 2, 3}),
@@ -532,6 +864,67 @@ func TestSerializeCasefold(t *testing.T) {
 	)
 }
 
+func TestSerializeSingleton(t *testing.T) {
+	assertJSON(t,
+		Singleton(Ref("classes/spells/42")),
+		`{"singleton":{"@ref":"classes/spells/42"}}`,
+	)
+}
+
+func TestSerializeContainsRef(t *testing.T) {
+	assertJSON(t,
+		ContainsRef(Match(Index("admins")), Var("ref")),
+		`{"exists":{"intersection":[{"match":{"index":"admins"}},{"singleton":{"var":"ref"}}]}}`,
+	)
+}
+
+func TestSerializeEqualsStructuredRefs(t *testing.T) {
+	spells := RefV{ID: "spells", Collection: &RefV{ID: "collections"}}
+
+	assertJSON(t,
+		Equals(RefV{ID: "42", Collection: &spells}, RefClass(Collection("spells"), "42")),
+		`{"equals":[`+
+			`{"@ref":{"collection":{"@ref":{"collection":{"@ref":"collections"},"id":"spells"}},"id":"42"}},`+
+			`{"id":"42","ref":{"collection":"spells"}}`+
+			`]}`,
+	)
+}
+
+func TestSerializeContainsStr(t *testing.T) {
+	assertJSON(t,
+		ContainsStr("Fireball", "ball"),
+		`{"containsstr":"Fireball","search":"ball"}`,
+	)
+}
+
+func TestSerializeContainsStrRegex(t *testing.T) {
+	assertJSON(t,
+		ContainsStrRegex("Fireball", "^F.*l$"),
+		`{"containsstrregex":"Fireball","pattern":"^F.*l$"}`,
+	)
+}
+
+func TestSerializeStartsWith(t *testing.T) {
+	assertJSON(t,
+		StartsWith("Fireball", "Fire"),
+		`{"search":"Fire","startswith":"Fireball"}`,
+	)
+}
+
+func TestSerializeEndsWith(t *testing.T) {
+	assertJSON(t,
+		EndsWith("Fireball", "ball"),
+		`{"endswith":"Fireball","search":"ball"}`,
+	)
+}
+
+func TestSerializeStringPredicateInFilter(t *testing.T) {
+	assertJSON(t,
+		Filter(Arr{"Fireball", "Water"}, Lambda("name", StartsWith(Var("name"), "Fire"))),
+		`{"collection":["Fireball","Water"],"filter":{"expr":{"search":"Fire","startswith":{"var":"name"}},"lambda":"name"}}`,
+	)
+}
+
 func TestSerializeTime(t *testing.T) {
 	assertJSON(t,
 		Time("1970-01-01T00:00:00+00:00"),
@@ -637,6 +1030,13 @@ func TestSerializeDistinct(t *testing.T) {
 	)
 }
 
+func TestSerializeDocuments(t *testing.T) {
+	assertJSON(t,
+		Documents(Collection("spells")),
+		`{"documents":{"collection":"spells"}}`,
+	)
+}
+
 func TestSerializeJoin(t *testing.T) {
 	assertJSON(t,
 		Join(
@@ -700,6 +1100,42 @@ func TestSerializeClass(t *testing.T) {
 	)
 }
 
+func TestSerializeCollection(t *testing.T) {
+	assertJSON(t,
+		Collection("test-collection"),
+		`{"collection":"test-collection"}`,
+	)
+}
+
+func TestSerializeRole(t *testing.T) {
+	assertJSON(t,
+		Role("test-role"),
+		`{"role":"test-role"}`,
+	)
+}
+
+func TestSerializeScopedRefs(t *testing.T) {
+	assertJSON(t,
+		Database("child-db", Scope(Database("parent-db"))),
+		`{"database":"child-db","scope":{"database":"parent-db"}}`,
+	)
+
+	assertJSON(t,
+		Index("test-index", Scope(Database("child-db"))),
+		`{"index":"test-index","scope":{"database":"child-db"}}`,
+	)
+
+	assertJSON(t,
+		Class("test-class", Scope(Database("child-db"))),
+		`{"class":"test-class","scope":{"database":"child-db"}}`,
+	)
+
+	assertJSON(t,
+		Collection("test-collection", Scope(Database("child-db"))),
+		`{"collection":"test-collection","scope":{"database":"child-db"}}`,
+	)
+}
+
 func TestSerializeEquals(t *testing.T) {
 	assertJSON(t,
 		Equals(Arr{"fire", "fire"}),
@@ -747,6 +1183,88 @@ func TestSerializeSelect(t *testing.T) {
 	)
 }
 
+func TestSerializeSelectWithDefault(t *testing.T) {
+	assertJSON(t,
+		SelectWithDefault(
+			Arr{"data", "spell"},
+			Get(Ref("classes/characters/1")),
+			Get(Ref("classes/spells/42")),
+		),
+		`{"default":{"get":{"@ref":"classes/spells/42"}},"from":{"get":{"@ref":"classes/characters/1"}},`+
+			`"select":["data","spell"]}`,
+	)
+}
+
+func TestSerializeSelectAll(t *testing.T) {
+	assertJSON(t,
+		SelectAll(
+			"name",
+			Arr{Obj{"name": "Fireball"}, Obj{"name": "Frostbolt"}},
+		),
+		`{"from":[{"object":{"name":"Fireball"}},{"object":{"name":"Frostbolt"}}],"select_all":"name"}`,
+	)
+
+	assertJSON(t,
+		SelectAll(Arr{"data", "spells", "name"}, Get(Ref("classes/books/1"))),
+		`{"from":{"get":{"@ref":"classes/books/1"}},"select_all":["data","spells","name"]}`,
+	)
+}
+
+func TestExprJSON(t *testing.T) {
+	tests := []struct {
+		expr Expr
+		json string
+	}{
+		{Ref("classes/spells/42"), `{"@ref":"classes/spells/42"}`},
+		{Get(Ref("classes/spells/42")), `{"get":{"@ref":"classes/spells/42"}}`},
+		{
+			Create(Class("spells"), Obj{"data": Obj{"name": "Fireball"}}),
+			`{"create":{"class":"spells"},"params":{"object":{"data":{"object":{"name":"Fireball"}}}}}`,
+		},
+		{
+			Map(Arr{1, 2}, Lambda("x", Add(Var("x"), 1))),
+			`{"collection":[1,2],"map":{"expr":{"add":[{"var":"x"},1]},"lambda":"x"}}`,
+		},
+		{
+			Paginate(MatchTerm(Index("spells_by_element"), "fire"), Size(10)),
+			`{"paginate":{"match":{"index":"spells_by_element"},"terms":"fire"},"size":10}`,
+		},
+	}
+
+	for _, test := range tests {
+		bytes, err := ExprJSON(test.expr)
+
+		require.NoError(t, err)
+		require.Equal(t, test.json, string(bytes))
+	}
+}
+
+func TestExprJSONReportsEncodingErrors(t *testing.T) {
+	_, err := ExprJSON(Obj{"element": testElement(42)})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Error while encoding enum faunadb.testElement: Unknown value 42")
+}
+
+func TestSerializeCreateCollection(t *testing.T) {
+	assertJSON(t,
+		CreateCollection(Obj{"name": "spells"}),
+		`{"create_collection":{"object":{"name":"spells"}}}`,
+	)
+}
+
+func TestSerializeFunction(t *testing.T) {
+	assertJSON(t, Function("double"), `{"function":"double"}`)
+	assertJSON(t, Function("double", Scope(Database("tenant"))), `{"function":"double","scope":{"database":"tenant"}}`)
+}
+
+func TestSerializeCreateIfAbsent(t *testing.T) {
+	assertJSON(t,
+		CreateIfAbsent(Class("spells"), CreateClass(Obj{"name": "spells"})),
+		`{"else":{"do":[{"create_class":{"object":{"name":"spells"}}},true]},"if":{"exists":{"class":"spells"}},"then":false}`,
+	)
+}
+
 func TestSerializeAdd(t *testing.T) {
 	assertJSON(t,
 		Add(Arr{1, 2}),
@@ -807,6 +1325,138 @@ func TestSerializeModulo(t *testing.T) {
 	)
 }
 
+func TestSerializeMax(t *testing.T) {
+	assertJSON(t,
+		Max(1, 2, 3),
+		`{"max":[1,2,3]}`,
+	)
+
+	assertJSON(t,
+		Max(Var("x")),
+		`{"max":[{"var":"x"}]}`,
+	)
+}
+
+func TestSerializeMin(t *testing.T) {
+	assertJSON(t,
+		Min(1, 2, 3),
+		`{"min":[1,2,3]}`,
+	)
+
+	assertJSON(t,
+		Min(Var("x")),
+		`{"min":[{"var":"x"}]}`,
+	)
+}
+
+func TestSerializeSetMax(t *testing.T) {
+	assertJSON(t,
+		SetMax(Arr{1, 2, 3}),
+		`{"max":[1,2,3]}`,
+	)
+
+	assertJSON(t,
+		SetMax(Match(Index("spells_by_level"))),
+		`{"max":{"match":{"index":"spells_by_level"}}}`,
+	)
+}
+
+func TestSerializeSetMin(t *testing.T) {
+	assertJSON(t,
+		SetMin(Var("levels")),
+		`{"min":{"var":"levels"}}`,
+	)
+}
+
+func TestSerializeFloor(t *testing.T) {
+	assertJSON(t,
+		Floor(1.5),
+		`{"floor":1.5}`,
+	)
+}
+
+func TestSerializeRefAccessors(t *testing.T) {
+	assertJSON(t,
+		CollectionOf(Ref("classes/spells/42")),
+		`{"from":{"@ref":"classes/spells/42"},"select":"collection"}`,
+	)
+
+	assertJSON(t,
+		ClassOf(Var("ref")),
+		`{"from":{"var":"ref"},"select":"class"}`,
+	)
+
+	assertJSON(t,
+		DatabaseOf(Var("ref")),
+		`{"default":null,"from":{"var":"ref"},"select":"database"}`,
+	)
+
+	assertJSON(t,
+		IDOf(Select("ref", Var("doc"))),
+		`{"from":{"from":{"var":"doc"},"select":"ref"},"select":"id"}`,
+	)
+
+	assertJSON(t,
+		Get(RefClass(CollectionOf(Var("ref")), "42")),
+		`{"get":{"id":"42","ref":{"from":{"var":"ref"},"select":"collection"}}}`,
+	)
+}
+
+func TestSerializeToObject(t *testing.T) {
+	assertJSON(t,
+		ToObject(Arr{Arr{"name", "Fireball"}, Arr{"level", 3}}),
+		`{"to_object":[["name","Fireball"],["level",3]]}`,
+	)
+}
+
+func TestSerializeToArray(t *testing.T) {
+	assertJSON(t,
+		ToArray(Obj{"name": "Fireball"}),
+		`{"to_array":{"object":{"name":"Fireball"}}}`,
+	)
+}
+
+func TestSerializeObjectToPairsAndBack(t *testing.T) {
+	assertJSON(t,
+		ToObject(
+			Filter(
+				ToArray(Select("data", Get(Ref("classes/spells/42")))),
+				Lambda(Arr{"key", "value"}, Not(Equals(Var("key"), "secret"))),
+			),
+		),
+		`{"to_object":{"collection":{"to_array":{"from":{"get":{"@ref":"classes/spells/42"}},"select":"data"}},`+
+			`"filter":{"expr":{"not":{"equals":[{"var":"key"},"secret"]}},"lambda":["key","value"]}}}`,
+	)
+}
+
+func TestSerializeTypePredicates(t *testing.T) {
+	predicates := []struct {
+		name      string
+		predicate func(interface{}) Expr
+	}{
+		{"is_number", IsNumber},
+		{"is_double", IsDouble},
+		{"is_integer", IsInteger},
+		{"is_string", IsString},
+		{"is_boolean", IsBoolean},
+		{"is_null", IsNull},
+		{"is_array", IsArray},
+		{"is_object", IsObject},
+		{"is_ref", IsRef},
+		{"is_set", IsSet},
+		{"is_timestamp", IsTimestamp},
+		{"is_date", IsDate},
+		{"is_bytes", IsBytes},
+	}
+
+	for _, test := range predicates {
+		assertJSON(t,
+			test.predicate(Var("value")),
+			`{"`+test.name+`":{"var":"value"}}`,
+		)
+	}
+}
+
 func TestSerializeLT(t *testing.T) {
 	assertJSON(t,
 		LT(Arr{1, 2}),
@@ -886,6 +1536,13 @@ func TestSerializeNot(t *testing.T) {
 	)
 }
 
+func toJSON(t *testing.T, expr Expr) string {
+	bytes, err := json.Marshal(expr)
+
+	require.NoError(t, err)
+	return string(bytes)
+}
+
 func assertJSON(t *testing.T, expr Expr, expected string) {
 	bytes, err := json.Marshal(expr)
 
@@ -0,0 +1,198 @@
+package faunadb
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+var errUnsupportedStreamConfig = errors.New("Error while streaming query: DryRun and UnwrapData configurations are not supported")
+
+/*
+ResultStream decodes the elements of an array returned by a query one at a time, without loading the
+whole response into memory. For example:
+
+	stream, err := client.QueryStream(Select("data", Paginate(Documents(Collection("spells")), Size(100000))))
+	if err != nil {
+		panic(err)
+	}
+
+	defer stream.Close()
+
+	for {
+		value, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			panic(err)
+		}
+
+		// use value
+	}
+
+ResultStreams are not safe for concurrent use.
+*/
+type ResultStream struct {
+	body    io.ReadCloser
+	parser  jsonParser
+	done    bool
+	release func()
+}
+
+/*
+QueryStream sends a query language expression to FaunaDB, returning a ResultStream over the elements of the
+array it evaluates to. The stream must be closed after use.
+
+It accepts the same configurations as Query, except for DryRun and UnwrapData, which return an error without
+sending the query. Failures to open the stream with transient errors are retried according to the client's
+Retries configuration; errors found while reading its elements are not.
+*/
+func (client *FaunaClient) QueryStream(expr Expr, configs ...QueryConfig) (stream *ResultStream, err error) {
+	cfg := newQueryConfig(configs)
+
+	if cfg.dryRun || cfg.unwrapData {
+		return nil, errUnsupportedStreamConfig
+	}
+
+	envelope := "resource"
+	if cfg.fql {
+		envelope = "data"
+	}
+
+	for retry := 1; ; retry++ {
+		stream, err = client.openStream(expr, cfg, envelope)
+
+		if err == nil || retry > client.retries || !isTransientError(err) {
+			return
+		}
+
+		if client.backoff != nil {
+			time.Sleep(client.backoff(retry))
+		}
+	}
+}
+
+func (client *FaunaClient) openStream(expr Expr, cfg *queryConfig, envelope string) (stream *ResultStream, err error) {
+	var request *http.Request
+	var response *http.Response
+
+	if request, err = client.prepareRequest(expr, cfg); err != nil {
+		return
+	}
+
+	if err = client.acquire(); err != nil {
+		return
+	}
+
+	if response, err = client.http.Do(request); err != nil {
+		client.release()
+		return
+	}
+
+	if err = checkForResponseErrors(response); err != nil {
+		_ = response.Body.Close()
+		client.release()
+		return
+	}
+
+	stream = newResultStream(response.Body)
+	stream.release = client.release
+
+	if err = stream.openEnvelope(envelope); err != nil {
+		_ = stream.Close()
+		stream = nil
+	}
+
+	return
+}
+
+func newResultStream(body io.ReadCloser) *ResultStream {
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+
+	return &ResultStream{
+		body:   body,
+		parser: jsonParser{decoder},
+	}
+}
+
+// openEnvelope advances the decoder to the first element of the array under the envelope key informed.
+func (stream *ResultStream) openEnvelope(envelope string) error {
+	if err := stream.expectDelim(json.Delim('{'), "an object"); err != nil {
+		return err
+	}
+
+	for stream.parser.decoder.More() {
+		key, err := stream.parser.readString()
+		if err != nil {
+			return err
+		}
+
+		if key == envelope {
+			return stream.expectDelim(json.Delim('['), "an array")
+		}
+
+		var ignored json.RawMessage
+		if err := stream.parser.decoder.Decode(&ignored); err != nil {
+			return err
+		}
+	}
+
+	return ValueNotFound{pathFromKeys(envelope), segmentNotFound{"Object key", objectSegment(envelope)}}
+}
+
+func (stream *ResultStream) expectDelim(delim json.Delim, expected string) error {
+	token, err := stream.parser.decoder.Token()
+
+	if err == nil && token != delim {
+		if got, ok := token.(json.Delim); ok {
+			token = got.String()
+		}
+
+		err = wrongToken{expected, token}
+	}
+
+	return err
+}
+
+// Next decodes the next element of the stream. It returns io.EOF when there are no more elements.
+// Once Next returns an error, the stream is closed and subsequent calls return io.EOF.
+func (stream *ResultStream) Next() (value Value, err error) {
+	if stream.done {
+		return nil, io.EOF
+	}
+
+	if !stream.parser.decoder.More() {
+		if _, err = stream.parser.decoder.Token(); err == nil { // Consumes the closing ] token
+			err = io.EOF
+		}
+	} else {
+		value, err = stream.parser.parseNext()
+	}
+
+	if err != nil {
+		_ = stream.Close()
+	}
+
+	return
+}
+
+// Close releases the resources held by the stream. Remaining elements are not read, so the
+// underlying connection is not reused when the stream is closed before its end.
+func (stream *ResultStream) Close() error {
+	if stream.done {
+		return nil
+	}
+
+	stream.done = true
+
+	if stream.release != nil {
+		defer stream.release()
+	}
+
+	return stream.body.Close()
+}
@@ -0,0 +1,177 @@
+package faunadb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamLargeArray(t *testing.T) {
+	elements := make([]string, 100000)
+
+	for i := range elements {
+		elements[i] = fmt.Sprintf(`{"@ref":"classes/spells/%d"}`, i)
+	}
+
+	server := newMockServer(`{"resource": [` + strings.Join(elements, ",") + `]}`)
+	defer server.Close()
+
+	stream, err := server.client().QueryStream(Select("data", Paginate(Documents(Collection("spells")))))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	count := 0
+
+	for {
+		value, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, RefV{ID: fmt.Sprintf("classes/spells/%d", count)}, value)
+		count++
+	}
+
+	require.Equal(t, len(elements), count)
+
+	_, err = stream.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestStreamNestedValues(t *testing.T) {
+	server := newMockServer(`{"resource": [[1, 2], {"name": "Fire"}, null]}`)
+	defer server.Close()
+
+	stream, err := server.client().QueryStream(NullV{})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	for _, expected := range []Value{ArrayV{LongV(1), LongV(2)}, ObjectV{"name": StringV("Fire")}, NullV{}} {
+		value, err := stream.Next()
+		require.NoError(t, err)
+		require.Equal(t, expected, value)
+	}
+
+	_, err = stream.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestStreamEmptyArray(t *testing.T) {
+	server := newMockServer(`{"resource": []}`)
+	defer server.Close()
+
+	stream, err := server.client().QueryStream(NullV{})
+	require.NoError(t, err)
+
+	_, err = stream.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestFailToStreamNonArrayResource(t *testing.T) {
+	server := newMockServer(`{"resource": {"name": "Fire"}}`)
+	defer server.Close()
+
+	stream, err := server.client().QueryStream(NullV{})
+	require.Nil(t, stream)
+	require.EqualError(t, err, "Expected an array but got \"{\"")
+}
+
+func TestFailToStreamResponseWithoutResource(t *testing.T) {
+	server := newMockServer(`{"other": [1]}`)
+	defer server.Close()
+
+	_, err := server.client().QueryStream(NullV{})
+	require.EqualError(t, err, "Error while extracting path: resource. Object key resource not found")
+}
+
+func TestFailToStreamTruncatedResponse(t *testing.T) {
+	server := newMockServer(`{"resource": [1, 2`)
+	defer server.Close()
+
+	stream, err := server.client().QueryStream(NullV{})
+	require.NoError(t, err)
+
+	value, err := stream.Next()
+	require.NoError(t, err)
+	require.Equal(t, LongV(1), value)
+
+	value, err = stream.Next()
+	require.NoError(t, err)
+	require.Equal(t, LongV(2), value)
+
+	_, err = stream.Next()
+	require.Error(t, err)
+	require.NotEqual(t, io.EOF, err)
+
+	_, err = stream.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestStreamReportsResponseErrors(t *testing.T) {
+	client := NewFaunaClient("secret", Endpoint("http://127.0.0.1:1"))
+
+	_, err := client.QueryStream(NullV{})
+	require.Error(t, err)
+}
+
+func TestRetryOpeningStreamOnTransientErrors(t *testing.T) {
+	transport := &sequenceTransport{transports: []http.RoundTripper{
+		UnavailableTransport(),
+		NetworkErrorTransport(syscall.ECONNRESET),
+		okTransport(`{"resource": [1, 2]}`),
+	}}
+
+	client := NewFaunaClientWithTransport("secret", transport, Retries(2, nil))
+
+	stream, err := client.QueryStream(NullV{})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	value, err := stream.Next()
+	require.NoError(t, err)
+	require.Equal(t, LongV(1), value)
+	require.Len(t, transport.requests, 3)
+}
+
+func TestDoNotRetryOpeningStreamWithoutRetries(t *testing.T) {
+	transport := &sequenceTransport{transports: []http.RoundTripper{
+		UnavailableTransport(),
+		okTransport(`{"resource": [1, 2]}`),
+	}}
+
+	_, err := NewFaunaClientWithTransport("secret", transport).QueryStream(NullV{})
+	require.IsType(t, Unavailable{}, err)
+	require.Len(t, transport.requests, 1)
+}
+
+func TestRejectUnsupportedStreamConfigs(t *testing.T) {
+	server := newMockServer(`{"resource": [1]}`)
+	defer server.Close()
+
+	for _, config := range []QueryConfig{DryRun(), UnwrapData()} {
+		stream, err := server.client().QueryStream(NullV{}, config)
+		require.Nil(t, stream)
+		require.Equal(t, errUnsupportedStreamConfig, err)
+	}
+
+	require.Empty(t, server.requestBodies())
+}
+
+func TestStreamFQLQueryData(t *testing.T) {
+	server := newMockServer(`{"data": [1, 2], "summary": ""}`)
+	defer server.Close()
+
+	stream, err := server.client(FQLEndpoint(server.URL)).QueryStream(NullV{}, func(cfg *queryConfig) { cfg.fql = true })
+	require.NoError(t, err)
+	defer stream.Close()
+
+	value, err := stream.Next()
+	require.NoError(t, err)
+	require.Equal(t, LongV(1), value)
+}
@@ -1,15 +1,105 @@
 package faunadb
 
-import "reflect"
+import (
+	"reflect"
+	"strings"
+	"time"
+)
 
 const faunaTag = "fauna"
 
-func fieldName(field reflect.StructField) string {
-	name := field.Tag.Get(faunaTag)
+// Options accepted after the field name in fauna struct tags, for example: `fauna:"created_at,unixmilli"`.
+const (
+	unixOption      = "unix"
+	unixMilliOption = "unixmilli"
+	unixMicroOption = "unixmicro"
+
+	secondsOption      = "seconds"
+	millisecondsOption = "milliseconds"
+	microsecondsOption = "microseconds"
+	nanosecondsOption  = "nanoseconds"
+
+	boolnumOption  = "boolnum"
+	requiredOption = "required"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+type tagOptions []string
+
+func (opts tagOptions) has(option string) bool {
+	for _, opt := range opts {
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// epochUnit returns the unit of integer fields holding timestamps as the number of units since the Unix epoch.
+func (opts tagOptions) epochUnit() (time.Duration, bool) {
+	switch {
+	case opts.has(unixOption):
+		return time.Second, true
+	case opts.has(unixMilliOption):
+		return time.Millisecond, true
+	case opts.has(unixMicroOption):
+		return time.Microsecond, true
+	default:
+		return 0, false
+	}
+}
+
+// durationUnit returns the unit of numbers held by time.Duration fields tagged with a unit. Untagged durations are
+// stored as their number of nanoseconds, like any other int64.
+func (opts tagOptions) durationUnit() (time.Duration, bool) {
+	switch {
+	case opts.has(secondsOption):
+		return time.Second, true
+	case opts.has(millisecondsOption):
+		return time.Millisecond, true
+	case opts.has(microsecondsOption):
+		return time.Microsecond, true
+	case opts.has(nanosecondsOption):
+		return time.Nanosecond, true
+	default:
+		return 0, false
+	}
+}
+
+func parseTag(field reflect.StructField) (name string, options tagOptions) {
+	parts := strings.Split(field.Tag.Get(faunaTag), ",")
+	name, options = parts[0], parts[1:]
 
 	if name == "" {
 		name = field.Name
 	}
 
+	return
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _ := parseTag(field)
 	return name
 }
+
+func timeFromEpoch(units int64, unit time.Duration) time.Time {
+	perSecond := int64(time.Second / unit)
+	return time.Unix(units/perSecond, (units%perSecond)*int64(unit)).UTC()
+}
+
+func epochFromTime(t time.Time, unit time.Duration) int64 {
+	perSecond := int64(time.Second / unit)
+	return t.Unix()*perSecond + int64(t.Nanosecond())/int64(unit)
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+package faunadb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+)
+
+// RoundTripperFunc adapts an ordinary function to the http.RoundTripper interface.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper by calling the function itself.
+func (fn RoundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return fn(request)
+}
+
+/*
+NewFaunaClientWithTransport creates a new FaunaClient structure that sends its requests through the http.RoundTripper
+informed. It accepts the same configurations as NewFaunaClient, except HTTP, which replaces the transport.
+
+Combined with ErrorTransport, UnavailableTransport, or NetworkErrorTransport, it can be used to test how applications
+handle FaunaDB failures without a running cluster. For example:
+
+	client := NewFaunaClientWithTransport("secret", UnavailableTransport())
+	_, err := client.Query(Get(Ref("classes/spells/42"))) // err is a faunadb.Unavailable error
+*/
+func NewFaunaClientWithTransport(secret string, transport http.RoundTripper, configs ...ClientConfig) *FaunaClient {
+	httpClient := &http.Client{
+		Transport: transport,
+		Timeout:   requestTimeout,
+	}
+
+	return NewFaunaClient(secret, append([]ClientConfig{HTTP(httpClient)}, configs...)...)
+}
+
+// ErrorTransport creates an http.RoundTripper that replies to every request with an error response
+// of the HTTP status informed, containing the query errors informed.
+func ErrorTransport(status int, errors ...QueryError) http.RoundTripper {
+	body := errorResponseBody(errors)
+
+	return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Status:        http.StatusText(status),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       request,
+		}, nil
+	})
+}
+
+// UnavailableTransport creates an http.RoundTripper that replies to every request with
+// the HTTP 503 error returned by FaunaDB when it is unavailable.
+func UnavailableTransport() http.RoundTripper {
+	return ErrorTransport(http.StatusServiceUnavailable, QueryError{
+		Position:    []string{},
+		Code:        "unavailable",
+		Description: "The service is unavailable.",
+	})
+}
+
+// NetworkErrorTransport creates an http.RoundTripper that fails every request with the error informed,
+// as if the connection to FaunaDB could not be established.
+func NetworkErrorTransport(err error) http.RoundTripper {
+	return RoundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, err })
+}
+
+func errorResponseBody(errors []QueryError) []byte {
+	encoded := make([]map[string]interface{}, len(errors))
+
+	for i, queryError := range errors {
+		position := queryError.Position
+		if position == nil {
+			position = []string{}
+		}
+
+		encoded[i] = map[string]interface{}{
+			"position":    position,
+			"code":        queryError.Code,
+			"description": queryError.Description,
+		}
+	}
+
+	body, _ := marshalJSON(map[string]interface{}{"errors": encoded})
+	return body
+}
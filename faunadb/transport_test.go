@@ -0,0 +1,59 @@
+package faunadb
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectUnavailableError(t *testing.T) {
+	client := NewFaunaClientWithTransport("secret", UnavailableTransport())
+
+	_, err := client.Query(Get(Ref("classes/spells/42")))
+
+	require.IsType(t, Unavailable{}, err)
+	require.Equal(t, 503, err.(FaunaError).Status())
+	require.Equal(t,
+		[]QueryError{{Position: []string{}, Code: "unavailable", Description: "The service is unavailable."}},
+		err.(FaunaError).Errors(),
+	)
+}
+
+func TestInjectErrorResponses(t *testing.T) {
+	client := NewFaunaClientWithTransport("secret", ErrorTransport(http.StatusNotFound, QueryError{
+		Position:    []string{"get"},
+		Code:        "instance not found",
+		Description: "Instance not found.",
+	}))
+
+	_, err := client.Query(Get(Ref("classes/spells/42")))
+
+	require.IsType(t, NotFound{}, err)
+	require.EqualError(t, err, "Response error 404. Errors: [get](instance not found): Instance not found.")
+}
+
+func TestInjectNetworkErrors(t *testing.T) {
+	client := NewFaunaClientWithTransport("secret", NetworkErrorTransport(errors.New("connection refused")))
+
+	_, err := client.Query(NullV{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "connection refused")
+}
+
+func TestTransportReceivesPreparedRequests(t *testing.T) {
+	var received *http.Request
+
+	transport := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		received = request
+		return ErrorTransport(http.StatusInternalServerError).RoundTrip(request)
+	})
+
+	client := NewFaunaClientWithTransport("secret", transport, Endpoint("http://localhost:8443"))
+
+	_, err := client.Query(NullV{})
+	require.IsType(t, InternalError{}, err)
+	require.Equal(t, "http://localhost:8443", received.URL.String())
+	require.Equal(t, BasicAuth("secret"), received.Header.Get("Authorization"))
+}
@@ -3,7 +3,11 @@ package faunadb
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
 	"time"
+	"unicode/utf8"
 )
 
 /*
@@ -24,14 +28,26 @@ The At method uses field extractors to transverse the data and reach to a more s
 	profile, _ := client.Query(Ref("classes/profile/43"))
 	profile.At(ObjKey("emails").AtIndex(0)).Get(&firstEmail)
 
+The AtPath method transverses the data using a path of keys and indexes, like the Select function does on the server:
+
+	profile.AtPath("emails", 0).Get(&firstEmail)
+
 For more information, check https://fauna.com/documentation/queries#values.
 */
 type Value interface {
 	Expr
 	Get(interface{}) error // Decode a FaunaDB value into a native Go type
 	At(Field) FieldValue   // Transverse the value using the field extractor informed
+
+	AtPath(path ...interface{}) FieldValue // Transverse the value using a path with the same semantics as Select
 }
 
+// Canonical text representations of dates and times used by FaunaDB.
+const (
+	dateFormat = "2006-01-02"
+	timeFormat = "2006-01-02T15:04:05.999999999Z"
+)
+
 // StringV represents a valid JSON string.
 type StringV string
 
@@ -41,6 +57,12 @@ func (str StringV) Get(i interface{}) error { return newValueDecoder(i).assign(s
 // At implements the Value interface by returning an invalid field since StringV is not transversable.
 func (str StringV) At(field Field) FieldValue { return field.get(str) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (str StringV) AtPath(path ...interface{}) FieldValue { return str.At(SelectPath(path...)) }
+
+// Len returns the number of unicode characters in the string.
+func (str StringV) Len() int { return utf8.RuneCountInString(string(str)) }
+
 // LongV represents a valid JSON number.
 type LongV int64
 
@@ -50,6 +72,9 @@ func (num LongV) Get(i interface{}) error { return newValueDecoder(i).assign(num
 // At implements the Value interface by returning an invalid field since LongV is not transversable.
 func (num LongV) At(field Field) FieldValue { return field.get(num) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (num LongV) AtPath(path ...interface{}) FieldValue { return num.At(SelectPath(path...)) }
+
 // DoubleV represents a valid JSON double.
 type DoubleV float64
 
@@ -59,6 +84,19 @@ func (num DoubleV) Get(i interface{}) error { return newValueDecoder(i).assign(n
 // At implements the Value interface by returning an invalid field since DoubleV is not transversable.
 func (num DoubleV) At(field Field) FieldValue { return field.get(num) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (num DoubleV) AtPath(path ...interface{}) FieldValue { return num.At(SelectPath(path...)) }
+
+// MarshalJSON implements json.Marshaler by encoding its value as a JSON number. NaN and infinite values can not be
+// represented in JSON, so they fail to encode with a descriptive error.
+func (num DoubleV) MarshalJSON() ([]byte, error) {
+	if f := float64(num); math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("Error while encoding number to json: Double value %v is not finite", f)
+	}
+
+	return json.Marshal(float64(num))
+}
+
 // BooleanV represents a valid JSON boolean.
 type BooleanV bool
 
@@ -68,37 +106,87 @@ func (boolean BooleanV) Get(i interface{}) error { return newValueDecoder(i).ass
 // At implements the Value interface by returning an invalid field since BooleanV is not transversable.
 func (boolean BooleanV) At(field Field) FieldValue { return field.get(boolean) }
 
-// DateV represents a FaunaDB date type.
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (boolean BooleanV) AtPath(path ...interface{}) FieldValue {
+	return boolean.At(SelectPath(path...))
+}
+
+// DateV represents a FaunaDB date type. Only the calendar date of the underlying time.Time is meaningful: it is taken
+// in the time's own location, so a time near midnight is not shifted to a different date by converting it to UTC.
+// Dates decoded from FaunaDB are at midnight UTC.
 type DateV time.Time
 
-// Get implements the Value interface by decoding the underlying value to either a DateV or a time.Time type.
+// Get implements the Value interface by decoding the underlying value to either a DateV or a time.Time type,
+// or to a string holding the date as formatted by FaunaDB, for example "2017-01-01".
 func (date DateV) Get(i interface{}) error { return newValueDecoder(i).assign(date) }
 
 // At implements the Value interface by returning an invalid field since DateV is not transversable.
 func (date DateV) At(field Field) FieldValue { return field.get(date) }
 
-// MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB date representation.
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (date DateV) AtPath(path ...interface{}) FieldValue { return date.At(SelectPath(path...)) }
+
+// MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB date representation,
+// using the calendar date of the time in its own location.
 func (date DateV) MarshalJSON() ([]byte, error) {
-	return escape("@date", time.Time(date).Format("2006-01-02"))
+	return escape("@date", time.Time(date).Format(dateFormat))
+}
+
+// ToStdTime returns the underlying time.Time of the date.
+func (date DateV) ToStdTime() time.Time { return time.Time(date) }
+
+// Before reports whether the calendar date is before the calendar date informed.
+func (date DateV) Before(other DateV) bool { return date.calendarDate().Before(other.calendarDate()) }
+
+// After reports whether the calendar date is after the calendar date informed.
+func (date DateV) After(other DateV) bool { return date.calendarDate().After(other.calendarDate()) }
+
+// Equal reports whether both dates have the same calendar date, regardless of their time and location.
+func (date DateV) Equal(other DateV) bool { return date.calendarDate().Equal(other.calendarDate()) }
+
+func (date DateV) calendarDate() time.Time {
+	year, month, day := time.Time(date).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 }
 
 // TimeV represents a FaunaDB time type.
 type TimeV time.Time
 
-// Get implements the Value interface by decoding the underlying value to either a TimeV or a time.Time type.
+// Get implements the Value interface by decoding the underlying value to either a TimeV or a time.Time type,
+// or to a string holding the time in UTC as formatted by FaunaDB, for example "2017-01-01T10:00:00.5Z".
 func (localTime TimeV) Get(i interface{}) error { return newValueDecoder(i).assign(localTime) }
 
 // At implements the Value interface by returning an invalid field since TimeV is not transversable.
 func (localTime TimeV) At(field Field) FieldValue { return field.get(localTime) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (localTime TimeV) AtPath(path ...interface{}) FieldValue {
+	return localTime.At(SelectPath(path...))
+}
+
 // MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB time representation.
 func (localTime TimeV) MarshalJSON() ([]byte, error) {
-	return escape("@ts", time.Time(localTime).Format("2006-01-02T15:04:05.999999999Z"))
+	return escape("@ts", time.Time(localTime).Format(timeFormat))
 }
 
-// RefV represents a FaunaDB ref type.
+// ToStdTime returns the underlying time.Time of the timestamp.
+func (localTime TimeV) ToStdTime() time.Time { return time.Time(localTime) }
+
+// Before reports whether the timestamp is before the timestamp informed.
+func (localTime TimeV) Before(other TimeV) bool { return time.Time(localTime).Before(time.Time(other)) }
+
+// After reports whether the timestamp is after the timestamp informed.
+func (localTime TimeV) After(other TimeV) bool { return time.Time(localTime).After(time.Time(other)) }
+
+// Equal reports whether the timestamp represents the same instant as the timestamp informed, regardless of their location.
+func (localTime TimeV) Equal(other TimeV) bool { return time.Time(localTime).Equal(time.Time(other)) }
+
+// RefV represents a FaunaDB ref type. Refs returned by newer versions of FaunaDB are structured:
+// besides its ID, a ref may point to the collection and the database it belongs to.
 type RefV struct {
-	ID string
+	ID         string
+	Collection *RefV
+	Database   *RefV
 }
 
 // Get implements the Value interface by decoding the underlying ref to a RefV.
@@ -107,8 +195,33 @@ func (ref RefV) Get(i interface{}) error { return newValueDecoder(i).assign(ref)
 // At implements the Value interface by returning an invalid field since RefV is not transversable.
 func (ref RefV) At(field Field) FieldValue { return field.get(ref) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (ref RefV) AtPath(path ...interface{}) FieldValue { return ref.At(SelectPath(path...)) }
+
 // MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB ref representation.
-func (ref RefV) MarshalJSON() ([]byte, error) { return escape("@ref", ref.ID) }
+// Refs without a collection or a database are escaped using the legacy string representation.
+func (ref RefV) MarshalJSON() ([]byte, error) {
+	if ref.Collection == nil && ref.Database == nil {
+		return escape("@ref", ref.ID)
+	}
+
+	structured := map[string]interface{}{"id": ref.ID}
+
+	if ref.Collection != nil {
+		structured["collection"] = ref.Collection
+	}
+
+	if ref.Database != nil {
+		structured["database"] = ref.Database
+	}
+
+	return escape("@ref", structured)
+}
+
+// Equal reports whether the ref represents the same ref as the ref informed, regardless of their representation.
+// Legacy refs are compared as their structured form: RefV{ID: "collections/spells/42"} is equal to
+// RefV{ID: "42", Collection: &RefV{ID: "spells", Collection: &RefV{ID: "collections"}}}.
+func (ref RefV) Equal(other RefV) bool { return refsEqual(&ref, &other) }
 
 // SetRefV represents a FaunaDB setref type.
 type SetRefV struct {
@@ -121,6 +234,9 @@ func (set SetRefV) Get(i interface{}) error { return newValueDecoder(i).assign(s
 // At implements the Value interface by returning an invalid field since SetRefV is not transversable.
 func (set SetRefV) At(field Field) FieldValue { return field.get(set) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (set SetRefV) AtPath(path ...interface{}) FieldValue { return set.At(SelectPath(path...)) }
+
 // MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB setref representation.
 func (set SetRefV) MarshalJSON() ([]byte, error) { return escape("@set", set.Parameters) }
 
@@ -133,9 +249,75 @@ func (obj ObjectV) Get(i interface{}) error { return newValueDecoder(i).decodeMa
 // At implements the Value interface by transversing the object and extracting the field informed.
 func (obj ObjectV) At(field Field) FieldValue { return field.get(obj) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (obj ObjectV) AtPath(path ...interface{}) FieldValue { return obj.At(SelectPath(path...)) }
+
 // MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB object representation.
 func (obj ObjectV) MarshalJSON() ([]byte, error) { return escape("object", map[string]Value(obj)) }
 
+// Len returns the number of keys in the object.
+func (obj ObjectV) Len() int { return len(obj) }
+
+// IsEmpty returns true if the object has no keys.
+func (obj ObjectV) IsEmpty() bool { return len(obj) == 0 }
+
+// Keys returns the keys of the object, sorted. FaunaDB has no function returning the keys of an object; to enumerate
+// them on the server, use ToArray, which converts the object into an array of [key, value] pairs.
+func (obj ObjectV) Keys() []string {
+	keys := make([]string, 0, len(obj))
+
+	for key := range obj {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Values returns the values of the object in the order of its sorted keys. See Keys.
+func (obj ObjectV) Values() []Value {
+	values := make([]Value, 0, len(obj))
+
+	for _, key := range obj.Keys() {
+		values = append(values, obj[key])
+	}
+
+	return values
+}
+
+/*
+Merge returns a new object with the keys of both objects, without modifying them. When both objects have the same key,
+the value of the other object is used, unless both values are objects: in that case, they are merged recursively.
+Arrays and scalars are not merged, but replaced. For example:
+
+	obj := ObjectV{"name": StringV("Fire"), "stats": ObjectV{"level": LongV(1), "cost": LongV(10)}}
+	obj.Merge(ObjectV{"stats": ObjectV{"level": LongV(2)}})
+	// ObjectV{"name": StringV("Fire"), "stats": ObjectV{"level": LongV(2), "cost": LongV(10)}}
+
+Unlike the server's Update function, a NullV value does not remove its key from the merged object.
+Values that did not need merging are shared with the original objects rather than copied.
+*/
+func (obj ObjectV) Merge(other ObjectV) ObjectV {
+	merged := make(ObjectV, len(obj)+len(other))
+
+	for key, value := range obj {
+		merged[key] = value
+	}
+
+	for key, value := range other {
+		current, currentIsObj := merged[key].(ObjectV)
+		patch, patchIsObj := value.(ObjectV)
+
+		if currentIsObj && patchIsObj {
+			merged[key] = current.Merge(patch)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
 // ArrayV represents a FaunaDB array type.
 type ArrayV []Value
 
@@ -145,6 +327,15 @@ func (arr ArrayV) Get(i interface{}) error { return newValueDecoder(i).decodeArr
 // At implements the Value interface by transversing the array and extracting the field informed.
 func (arr ArrayV) At(field Field) FieldValue { return field.get(arr) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (arr ArrayV) AtPath(path ...interface{}) FieldValue { return arr.At(SelectPath(path...)) }
+
+// Len returns the number of elements in the array.
+func (arr ArrayV) Len() int { return len(arr) }
+
+// IsEmpty returns true if the array has no elements.
+func (arr ArrayV) IsEmpty() bool { return len(arr) == 0 }
+
 // NullV represents a valid JSON null.
 type NullV struct{}
 
@@ -154,6 +345,9 @@ func (null NullV) Get(i interface{}) error { return nil }
 // At implements the Value interface by returning an invalid field since NullV is not transversable.
 func (null NullV) At(field Field) FieldValue { return field.get(null) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (null NullV) AtPath(path ...interface{}) FieldValue { return null.At(SelectPath(path...)) }
+
 // MarshalJSON implements json.Marshaler by escaping its value according to JSON null representation.
 func (null NullV) MarshalJSON() ([]byte, error) { return []byte("null"), nil }
 
@@ -166,6 +360,9 @@ func (bytes BytesV) Get(i interface{}) error { return newValueDecoder(i).assign(
 // At implements the Value interface by returning an invalid field since BytesV is not transversable.
 func (bytes BytesV) At(field Field) FieldValue { return field.get(bytes) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (bytes BytesV) AtPath(path ...interface{}) FieldValue { return bytes.At(SelectPath(path...)) }
+
 // MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB bytes representation.
 func (bytes BytesV) MarshalJSON() ([]byte, error) {
 	encoded := base64.StdEncoding.EncodeToString(bytes)
@@ -183,6 +380,9 @@ func (query QueryV) Get(i interface{}) error { return newValueDecoder(i).assign(
 // At implements the Value interface by returning an invalid field since QueryV is not transversable.
 func (query QueryV) At(field Field) FieldValue { return field.get(query) }
 
+// AtPath implements the Value interface by transversing the value with a SelectPath field extractor.
+func (query QueryV) AtPath(path ...interface{}) FieldValue { return query.At(SelectPath(path...)) }
+
 // MarshalJSON implements json.Marshaler by escaping its value according to FaunaDB query representation.
 func (query QueryV) MarshalJSON() ([]byte, error) { return escape("@query", &query.lambda) }
 
@@ -203,5 +403,5 @@ func (bytes BytesV) expr()     {}
 func (query QueryV) expr()     {}
 
 func escape(key string, value interface{}) ([]byte, error) {
-	return json.Marshal(map[string]interface{}{key: value})
+	return marshalJSON(map[string]interface{}{key: value})
 }
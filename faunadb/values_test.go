@@ -0,0 +1,188 @@
+package faunadb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringLenCountsCharacters(t *testing.T) {
+	require.Equal(t, 0, StringV("").Len())
+	require.Equal(t, 5, StringV("spell").Len())
+	require.Equal(t, 7, StringV("feitiço").Len())
+	require.Equal(t, 2, StringV("魔法").Len())
+}
+
+func TestArrayLen(t *testing.T) {
+	require.Equal(t, 0, ArrayV{}.Len())
+	require.Equal(t, 0, ArrayV(nil).Len())
+	require.Equal(t, 2, ArrayV{LongV(1), LongV(2)}.Len())
+}
+
+func TestArrayIsEmpty(t *testing.T) {
+	require.True(t, ArrayV{}.IsEmpty())
+	require.True(t, ArrayV(nil).IsEmpty())
+	require.False(t, ArrayV{NullV{}}.IsEmpty())
+}
+
+func TestObjectLen(t *testing.T) {
+	require.Equal(t, 0, ObjectV{}.Len())
+	require.Equal(t, 0, ObjectV(nil).Len())
+	require.Equal(t, 2, ObjectV{"a": LongV(1), "b": LongV(2)}.Len())
+}
+
+func TestObjectIsEmpty(t *testing.T) {
+	require.True(t, ObjectV{}.IsEmpty())
+	require.True(t, ObjectV(nil).IsEmpty())
+	require.False(t, ObjectV{"a": NullV{}}.IsEmpty())
+}
+
+func TestObjectKeysAreSorted(t *testing.T) {
+	obj := ObjectV{"name": StringV("Fire"), "cost": LongV(10), "Level": LongV(1), "active": BooleanV(true)}
+
+	for i := 0; i < 10; i++ {
+		require.Equal(t, []string{"Level", "active", "cost", "name"}, obj.Keys())
+	}
+}
+
+func TestObjectValuesFollowSortedKeys(t *testing.T) {
+	obj := ObjectV{"name": StringV("Fire"), "cost": LongV(10), "Level": LongV(1), "active": BooleanV(true)}
+
+	for i := 0; i < 10; i++ {
+		require.Equal(t, []Value{LongV(1), BooleanV(true), LongV(10), StringV("Fire")}, obj.Values())
+	}
+}
+
+func TestEmptyObjectKeysAndValues(t *testing.T) {
+	require.Equal(t, []string{}, ObjectV(nil).Keys())
+	require.Equal(t, []Value{}, ObjectV{}.Values())
+}
+
+func TestCompareTimes(t *testing.T) {
+	instant := time.Date(2017, time.January, 1, 10, 0, 0, 500000000, time.UTC)
+
+	earlier := TimeV(instant.Add(-time.Nanosecond))
+	later := TimeV(instant.Add(time.Microsecond))
+	current := TimeV(instant)
+
+	require.True(t, earlier.Before(current))
+	require.False(t, current.Before(earlier))
+	require.True(t, later.After(current))
+	require.False(t, current.After(later))
+	require.False(t, current.Before(current))
+	require.False(t, current.After(current))
+}
+
+func TestTimesEqualAcrossPrecisionsAndLocations(t *testing.T) {
+	nanos := TimeV(time.Date(2017, time.January, 1, 10, 0, 0, 500000000, time.UTC))
+	millis := TimeV(time.Unix(0, 0).Add(time.Duration(1483264800500) * time.Millisecond))
+	zoned := TimeV(time.Date(2017, time.January, 1, 8, 0, 0, 500000000, time.FixedZone("BRST", -2*60*60)))
+
+	require.True(t, nanos.Equal(millis))
+	require.True(t, nanos.Equal(zoned))
+	require.False(t, nanos.Equal(TimeV(time.Time(nanos).Add(time.Nanosecond))))
+}
+
+func TestTimeToStdTime(t *testing.T) {
+	instant := time.Date(2017, time.January, 1, 10, 0, 0, 1, time.UTC)
+	require.Equal(t, instant, TimeV(instant).ToStdTime())
+}
+
+func TestCompareDates(t *testing.T) {
+	date := DateV(time.Date(2017, time.January, 2, 0, 0, 0, 0, time.UTC))
+	before := DateV(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	after := DateV(time.Date(2017, time.January, 3, 0, 0, 0, 0, time.UTC))
+
+	require.True(t, before.Before(date))
+	require.True(t, after.After(date))
+	require.False(t, date.Before(before))
+	require.False(t, date.After(after))
+	require.True(t, date.Equal(DateV(time.Date(2017, time.January, 2, 0, 0, 0, 0, time.UTC))))
+	require.False(t, date.Equal(after))
+	require.Equal(t, time.Time(date), date.ToStdTime())
+}
+
+func TestMergeObjects(t *testing.T) {
+	obj := ObjectV{
+		"name":  StringV("Fire"),
+		"tags":  ArrayV{StringV("hot"), StringV("red")},
+		"stats": ObjectV{"level": LongV(1), "cost": LongV(10), "bonus": ObjectV{"fire": LongV(1)}},
+		"owner": StringV("Merlin"),
+	}
+
+	merged := obj.Merge(ObjectV{
+		"tags":  ArrayV{StringV("blue")},
+		"stats": ObjectV{"level": LongV(2), "bonus": ObjectV{"water": LongV(3)}},
+		"owner": ObjectV{"name": StringV("Morgana")},
+		"extra": NullV{},
+	})
+
+	require.Equal(t, ObjectV{
+		"name":  StringV("Fire"),
+		"tags":  ArrayV{StringV("blue")},
+		"stats": ObjectV{"level": LongV(2), "cost": LongV(10), "bonus": ObjectV{"fire": LongV(1), "water": LongV(3)}},
+		"owner": ObjectV{"name": StringV("Morgana")},
+		"extra": NullV{},
+	}, merged)
+}
+
+func TestMergeDoesNotModifyObjects(t *testing.T) {
+	obj := ObjectV{"stats": ObjectV{"level": LongV(1)}}
+	other := ObjectV{"stats": ObjectV{"cost": LongV(10)}}
+
+	merged := obj.Merge(other)
+	merged["stats"].(ObjectV)["level"] = LongV(3)
+
+	require.Equal(t, ObjectV{"stats": ObjectV{"level": LongV(1)}}, obj)
+	require.Equal(t, ObjectV{"stats": ObjectV{"cost": LongV(10)}}, other)
+}
+
+func TestMergeEmptyObjects(t *testing.T) {
+	require.Equal(t, ObjectV{}, ObjectV(nil).Merge(nil))
+	require.Equal(t, ObjectV{"a": LongV(1)}, ObjectV{"a": LongV(1)}.Merge(ObjectV{}))
+	require.Equal(t, ObjectV{"a": LongV(1)}, ObjectV{}.Merge(ObjectV{"a": LongV(1)}))
+}
+
+func TestMarshalDatesNearMidnightInTheirOwnLocation(t *testing.T) {
+	plus13 := time.FixedZone("+13", 13*60*60)
+	minus11 := time.FixedZone("-11", -11*60*60)
+
+	tests := []struct {
+		date     time.Time
+		expected string
+	}{
+		{time.Date(2017, time.January, 1, 0, 30, 0, 0, plus13), `{"@date":"2017-01-01"}`},   // 2016-12-31 in UTC
+		{time.Date(2017, time.January, 1, 23, 30, 0, 0, plus13), `{"@date":"2017-01-01"}`},  // 2017-01-01 in UTC
+		{time.Date(2017, time.January, 1, 0, 30, 0, 0, minus11), `{"@date":"2017-01-01"}`},  // 2017-01-01 in UTC
+		{time.Date(2017, time.January, 1, 23, 30, 0, 0, minus11), `{"@date":"2017-01-01"}`}, // 2017-01-02 in UTC
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, toJSON(t, DateV(test.date)))
+	}
+}
+
+func TestDecodeMarshaledDatesNearMidnight(t *testing.T) {
+	for _, zone := range []*time.Location{time.FixedZone("+13", 13*60*60), time.FixedZone("-11", -11*60*60)} {
+		for _, hour := range []int{0, 23} {
+			date := DateV(time.Date(2017, time.January, 1, hour, 30, 0, 0, zone))
+
+			value, err := ParseValue(strings.NewReader(toJSON(t, date)))
+			require.NoError(t, err)
+
+			require.Equal(t, DateV(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)), value)
+			require.True(t, value.(DateV).Equal(date))
+		}
+	}
+}
+
+func TestCompareDatesByCalendarDate(t *testing.T) {
+	lateInSamoa := DateV(time.Date(2017, time.January, 1, 23, 30, 0, 0, time.FixedZone("-11", -11*60*60)))
+	earlyInTonga := DateV(time.Date(2017, time.January, 2, 0, 30, 0, 0, time.FixedZone("+13", 13*60*60)))
+
+	require.True(t, lateInSamoa.Before(earlyInTonga)) // Even though it is a later instant
+	require.True(t, earlyInTonga.After(lateInSamoa))
+	require.False(t, lateInSamoa.Equal(earlyInTonga))
+}